@@ -2,4 +2,9 @@ module github.com/kanna5/advent_of_code/2023
 
 go 1.25
 
-require golang.org/x/exp v0.0.0-20251125195548-87e1e737ad39
+require (
+	github.com/kanna5/advent_of_code/common v0.0.0
+	golang.org/x/exp v0.0.0-20251125195548-87e1e737ad39
+)
+
+replace github.com/kanna5/advent_of_code/common => ../common
@@ -0,0 +1,50 @@
+package lib
+
+import (
+	"bufio"
+	"io"
+	"iter"
+)
+
+// Lines lazily yields each line of input, without the line terminator.
+// Scanner errors are yielded once, after which iteration stops.
+func Lines(input io.Reader) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		sc := bufio.NewScanner(input)
+		for sc.Scan() {
+			if !yield(sc.Text(), nil) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			yield("", err)
+		}
+	}
+}
+
+// Blocks lazily yields groups of consecutive non-empty lines. Blocks are
+// separated by one or more empty lines, which are never yielded.
+func Blocks(input io.Reader) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		var block []string
+		for line, err := range Lines(input) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(line) > 0 {
+				block = append(block, line)
+				continue
+			}
+			if len(block) > 0 {
+				if !yield(block, nil) {
+					return
+				}
+				block = nil
+			}
+		}
+		if len(block) > 0 {
+			yield(block, nil)
+		}
+	}
+}
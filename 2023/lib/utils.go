@@ -2,8 +2,10 @@ package lib
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"slices"
 	"strconv"
 	"strings"
@@ -75,3 +77,45 @@ func LcmSeq[T constraints.Integer](nums []T) T {
 		return Abs(a) * Abs(b) / Gcd(a, b)
 	}
 }
+
+// ErrNoSolution is returned by Crt when the congruences contradict each other.
+var ErrNoSolution = errors.New("no solution")
+
+// Crt finds the smallest non-negative x such that x = residues[i] (mod
+// moduli[i]) for every i, with moduli that are not necessarily coprime. It also
+// returns the least common multiple of the moduli, the period of solutions.
+// ref: https://en.wikipedia.org/wiki/Chinese_remainder_theorem#Generalization_to_non-coprime_moduli
+func Crt(residues, moduli []int64) (int64, int64, error) {
+	if len(residues) != len(moduli) {
+		return 0, 0, fmt.Errorf("got %d residues for %d moduli", len(residues), len(moduli))
+	}
+	x, m := big.NewInt(0), big.NewInt(1)
+	g, k, t := new(big.Int), new(big.Int), new(big.Int)
+	for i := range moduli {
+		if moduli[i] <= 0 {
+			return 0, 0, fmt.Errorf("invalid modulus %d", moduli[i])
+		}
+		a, n := big.NewInt(residues[i]), big.NewInt(moduli[i])
+
+		// Solve x + m*k = a (mod n)
+		g.GCD(nil, nil, m, n)
+		t.Sub(a, x)
+		if new(big.Int).Mod(t, g).Sign() != 0 {
+			return 0, 0, fmt.Errorf("%w: x = %d (mod %d) contradicts the previous congruences", ErrNoSolution, residues[i], moduli[i])
+		}
+		ng := new(big.Int).Quo(n, g)
+		k.SetInt64(0)
+		if ng.Cmp(big.NewInt(1)) != 0 {
+			k.ModInverse(new(big.Int).Quo(m, g), ng)
+			k.Mul(k, t.Quo(t, g))
+			k.Mod(k, ng)
+		}
+		x.Add(x, k.Mul(k, m))
+		m.Mul(m, ng)
+		x.Mod(x, m)
+	}
+	if !m.IsInt64() {
+		return 0, 0, fmt.Errorf("the least common multiple of the moduli overflows")
+	}
+	return x.Int64(), m.Int64(), nil
+}
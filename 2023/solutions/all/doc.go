@@ -0,0 +1,2 @@
+// Package all registers every day of 2023 on import.
+package all
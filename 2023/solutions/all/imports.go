@@ -1,4 +1,4 @@
-package main
+package all
 
 import (
 	_ "github.com/kanna5/advent_of_code/2023/solutions/day01"
@@ -2,11 +2,12 @@
 package day01
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 
+	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -14,12 +15,13 @@ type sol struct {
 }
 
 func (s *sol) SolvePart1() (string, error) {
-	sc := bufio.NewScanner(s.input)
 	var sum int64 = 0
 
-	for sc.Scan() {
+	for line, err := range lib.Lines(s.input) {
+		if err != nil {
+			return "", err
+		}
 		var first, last *int64
-		line := sc.Text()
 		for _, c := range line {
 			digit := int64(c - '0')
 			if digit <= 0 || digit > 9 {
@@ -36,9 +38,6 @@ func (s *sol) SolvePart1() (string, error) {
 		}
 		sum += *first*10 + *last
 	}
-	if err := sc.Err(); err != nil {
-		return "", err
-	}
 
 	return fmt.Sprintf("%d", sum), nil
 }
@@ -76,10 +75,12 @@ func parseDigit(str []rune) *int64 {
 
 func (s *sol) SolvePart2() (string, error) {
 	var sum int64 = 0
-	sc := bufio.NewScanner(s.input)
-	for sc.Scan() {
+	for text, err := range lib.Lines(s.input) {
+		if err != nil {
+			return "", err
+		}
 		var first, last *int64
-		line := []rune(sc.Text())
+		line := []rune(text)
 
 		for ; len(line) > 0; line = line[1:] {
 			n := parseDigit(line)
@@ -93,22 +94,20 @@ func (s *sol) SolvePart2() (string, error) {
 		}
 
 		if first == nil || last == nil {
-			return "", fmt.Errorf("invalid input line %v: no enough digits", string(line))
+			return "", fmt.Errorf("invalid input line %v: no enough digits", text)
 		}
 		sum += *first*10 + *last
 	}
-	if err := sc.Err(); err != nil {
-		return "", err
-	}
 
 	return fmt.Sprintf("%d", sum), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[1] = &sol{}
+	registry.Register(solutions.Year, 1, &sol{}, registry.Meta{
+		Title: "Trebuchet?!",
+	})
 }
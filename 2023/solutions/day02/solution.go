@@ -2,14 +2,15 @@
 package day02
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"log"
 	"strconv"
 	"strings"
 
+	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -68,10 +69,11 @@ func parseGame(game string, targetRGB [3]int64) (gameId int64, possible bool, po
 
 func (s *sol) SolvePart1() (string, error) {
 	var sum int64 = 0
-	sc := bufio.NewScanner(s.input)
 	target := [3]int64{12, 13, 14}
-	for sc.Scan() {
-		line := sc.Text()
+	for line, err := range lib.Lines(s.input) {
+		if err != nil {
+			return "", err
+		}
 		if gameId, possible, _ := parseGame(line, target); possible {
 			sum += gameId
 		} else if gameId == 0 {
@@ -83,10 +85,11 @@ func (s *sol) SolvePart1() (string, error) {
 
 func (s *sol) SolvePart2() (string, error) {
 	var sum int64 = 0
-	sc := bufio.NewScanner(s.input)
 	target := [3]int64{0, 0, 0} // noop
-	for sc.Scan() {
-		line := sc.Text()
+	for line, err := range lib.Lines(s.input) {
+		if err != nil {
+			return "", err
+		}
 		if gameId, _, power := parseGame(line, target); gameId != 0 {
 			sum += power
 		} else {
@@ -96,11 +99,12 @@ func (s *sol) SolvePart2() (string, error) {
 	return fmt.Sprintf("%d", sum), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[2] = &sol{}
+	registry.Register(solutions.Year, 2, &sol{}, registry.Meta{
+		Title: "Cube Conundrum",
+	})
 }
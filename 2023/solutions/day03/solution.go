@@ -9,15 +9,15 @@ import (
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
 	input io.Reader
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 type ObjType uint8
@@ -175,5 +175,8 @@ func (s *sol) SolvePart2() (string, error) {
 }
 
 func init() {
-	solutions.Days[3] = &sol{}
+	registry.Register(solutions.Year, 3, &sol{}, registry.Meta{
+		Title: "Gear Ratios",
+		Tags:  []registry.Tag{registry.Grid},
+	})
 }
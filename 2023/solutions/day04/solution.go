@@ -2,23 +2,23 @@
 package day04
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"iter"
 	"strconv"
 	"strings"
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
 	input io.Reader
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func nMatches(card string) (int64, error) {
@@ -44,13 +44,29 @@ func nMatches(card string) (int64, error) {
 	return ret, nil
 }
 
+func iterInput(input io.Reader) iter.Seq2[int64, error] {
+	return func(yield func(int64, error) bool) {
+		for line, err := range lib.Lines(input) {
+			if err != nil {
+				yield(0, err)
+				return
+			}
+			matches, err := nMatches(line)
+			if err != nil {
+				yield(0, err)
+				return
+			}
+			if !yield(matches, nil) {
+				return
+			}
+		}
+	}
+}
+
 func (s *sol) SolvePart1() (string, error) {
 	var sum int64
 
-	sc := bufio.NewScanner(s.input)
-	for sc.Scan() {
-		line := sc.Text()
-		matches, err := nMatches(line)
+	for matches, err := range iterInput(s.input) {
 		if err != nil {
 			return "", err
 		}
@@ -58,41 +74,34 @@ func (s *sol) SolvePart1() (string, error) {
 			sum += 1 << (matches - 1)
 		}
 	}
-	if err := sc.Err(); err != nil {
-		return "", err
-	}
 
 	return strconv.FormatInt(sum, 10), nil
 }
 
 func (s *sol) SolvePart2() (string, error) {
-	matches := make([]int64, 0, 1024)
+	// won[j] is the number of copies won for the j-th card after the current
+	// one. It never grows longer than the largest number of matches.
+	won := make([]int64, 0, 16)
 
-	sc := bufio.NewScanner(s.input)
-	for sc.Scan() {
-		line := sc.Text()
-		m, err := nMatches(line)
+	var sum int64
+	for m, err := range iterInput(s.input) {
 		if err != nil {
 			return "", err
 		}
-		matches = append(matches, m)
-	}
-	if err := sc.Err(); err != nil {
-		return "", err
-	}
 
-	nCards := make([]int64, len(matches))
-	for i := 0; i < len(nCards); i += 1 {
-		nCards[i] = 1
-	}
-	var sum int64
-	for i := 0; i < len(nCards); i += 1 {
-		nI := nCards[i]
+		var nI int64 = 1
+		if len(won) > 0 {
+			nI += won[0]
+			won = won[1:]
+		}
 		sum += nI
 
-		m := matches[i]
-		for j := 1; int64(j) <= m && i+j < len(nCards); j += 1 {
-			nCards[i+j] += nI
+		for j := range int(m) {
+			if j < len(won) {
+				won[j] += nI
+			} else {
+				won = append(won, nI)
+			}
 		}
 	}
 
@@ -100,5 +109,7 @@ func (s *sol) SolvePart2() (string, error) {
 }
 
 func init() {
-	solutions.Days[4] = &sol{}
+	registry.Register(solutions.Year, 4, &sol{}, registry.Meta{
+		Title: "Scratchcards",
+	})
 }
@@ -11,15 +11,15 @@ import (
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
 	input io.Reader
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 type Range struct {
@@ -172,5 +172,8 @@ func (s *sol) SolvePart2() (string, error) {
 }
 
 func init() {
-	solutions.Days[5] = &sol{}
+	registry.Register(solutions.Year, 5, &sol{}, registry.Meta{
+		Title: "If You Give A Seed A Fertilizer",
+		Tags:  []registry.Tag{registry.Math},
+	})
 }
@@ -4,7 +4,6 @@
 package day06
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"math"
@@ -13,6 +12,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -40,27 +40,27 @@ func (g Game) Solve() int64 {
 }
 
 func (s *sol) readInput() ([]Game, error) {
-	sc := bufio.NewScanner(s.input)
-	if !sc.Scan() {
-		if e := sc.Err(); e != nil {
-			return nil, e
+	var times, distances []int64
+	for line, err := range lib.Lines(s.input) {
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to read input")
-	}
-	times, err := lib.StrToIntSlice[int64](sc.Text()[len("Time:"):])
-	if err != nil {
-		return nil, err
-	}
-
-	if !sc.Scan() {
-		if e := sc.Err(); e != nil {
-			return nil, e
+		switch {
+		case strings.HasPrefix(line, "Time:"):
+			times, err = lib.StrToIntSlice[int64](line[len("Time:"):])
+		case strings.HasPrefix(line, "Distance:"):
+			distances, err = lib.StrToIntSlice[int64](line[len("Distance:"):])
+		case len(line) == 0:
+			continue
+		default:
+			err = fmt.Errorf("unexpected line %q", line)
+		}
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to read input")
 	}
-	distances, err := lib.StrToIntSlice[int64](sc.Text()[len("Distance:"):])
-	if err != nil {
-		return nil, err
+	if times == nil || distances == nil {
+		return nil, fmt.Errorf("failed to read input")
 	}
 
 	if len(times) != len(distances) {
@@ -111,11 +111,13 @@ func (s *sol) SolvePart2() (string, error) {
 	return strconv.FormatInt(ret, 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[6] = &sol{}
+	registry.Register(solutions.Year, 6, &sol{}, registry.Meta{
+		Title: "Wait For It",
+		Tags:  []registry.Tag{registry.Math},
+	})
 }
@@ -0,0 +1,208 @@
+package day07
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kanna5/advent_of_code/common/registry"
+)
+
+type TieBreak uint8
+
+const (
+	TieDealt  TieBreak = iota // Compare the cards in the order they are dealt
+	TieSorted                 // Compare the cards from the strongest
+)
+
+var tieBreaks = map[string]TieBreak{"dealt": TieDealt, "sorted": TieSorted}
+
+// Ruleset of a game of camel cards.
+type Ruleset struct {
+	Order     string // Card labels from the weakest to the strongest
+	Wildcards string // Cards which act like whatever card makes the hand strongest
+	HandSize  int
+
+	// Categories of hands from the strongest, as the counts of same cards in
+	// decreasing order, e.g. [3 2] for a full house. Hands in no category rank
+	// below all others. If empty, any counts make a category, and those with
+	// more same cards first are stronger: five of a kind beats four of a kind,
+	// which beats a full house, and so on.
+	Categories [][]int
+
+	TieBreak TieBreak
+}
+
+var defaultRules = [...]Ruleset{
+	1: {Order: "23456789TJQKA", HandSize: 5},
+	2: {Order: "J23456789TQKA", Wildcards: "J", HandSize: 5},
+}
+
+var optionKeys = []string{"order", "wildcards", "size", "categories", "tiebreak"}
+
+// With returns the ruleset changed by the options:
+//
+//	order=23456789TJQKA      card labels from the weakest
+//	wildcards=J              wildcard labels, can be empty
+//	size=5                   number of cards in a hand
+//	categories=5,41,32,...   hand categories from the strongest, one digit per count
+//	tiebreak=dealt|sorted    order of the cards compared between hands of the same category
+func (r Ruleset) With(opts registry.Options) (Ruleset, error) {
+	if err := opts.Check(optionKeys...); err != nil {
+		return r, err
+	}
+	if v, ok := opts["order"]; ok {
+		r.Order = v
+	}
+	if v, ok := opts["wildcards"]; ok {
+		r.Wildcards = v
+	}
+	if v, ok := opts["size"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return r, fmt.Errorf("invalid hand size %q", v)
+		}
+		r.HandSize = n
+	}
+	if v, ok := opts["categories"]; ok {
+		r.Categories = nil
+		for c := range strings.SplitSeq(v, ",") {
+			counts := make([]int, 0, len(c))
+			for _, d := range c {
+				if d < '1' || d > '9' {
+					return r, fmt.Errorf("invalid hand category %q", c)
+				}
+				counts = append(counts, int(d-'0'))
+			}
+			r.Categories = append(r.Categories, counts)
+		}
+	}
+	if v, ok := opts["tiebreak"]; ok {
+		tb, ok := tieBreaks[v]
+		if !ok {
+			return r, fmt.Errorf("invalid tie-break %q, can be dealt or sorted", v)
+		}
+		r.TieBreak = tb
+	}
+	return r, r.validate()
+}
+
+func (r *Ruleset) validate() error {
+	for i, l := range r.Order {
+		if strings.ContainsRune(r.Order[i+1:], l) {
+			return fmt.Errorf("card %q is ordered twice", l)
+		}
+	}
+	for _, l := range r.Wildcards {
+		if !strings.ContainsRune(r.Order, l) {
+			return fmt.Errorf("wildcard %q is not an ordered card", l)
+		}
+	}
+	for _, c := range r.Categories {
+		sum := 0
+		for _, n := range c {
+			sum += n
+		}
+		if sum != r.HandSize || !slices.IsSortedFunc(c, func(a, b int) int { return b - a }) {
+			return fmt.Errorf("invalid hand category %v for hands of %d cards", c, r.HandSize)
+		}
+	}
+	return nil
+}
+
+type Hand struct {
+	cards    []int // Strength of the cards, in the order compared by the tie-break
+	category []int // Counts of same cards in decreasing order, wildcards included
+	rank     int   // Index of the category in the ruleset from the weakest, or -1
+	bid      int64
+}
+
+func compareHands(a, b *Hand) int {
+	return cmp.Or(
+		cmp.Compare(a.rank, b.rank),
+		slices.Compare(a.category, b.category),
+		slices.Compare(a.cards, b.cards),
+	)
+}
+
+func (r *Ruleset) rankOf(counts []int) int {
+	i := slices.IndexFunc(r.Categories, func(c []int) bool { return slices.Equal(c, counts) })
+	if i == -1 {
+		return -1
+	}
+	return len(r.Categories) - 1 - i
+}
+
+// categorize finds the strongest category of a hand, trying every way to turn
+// the wildcards into other cards.
+func (r *Ruleset) categorize(labels []rune) ([]int, int) {
+	count := map[rune]int{}
+	wild := 0
+	for _, l := range labels {
+		if strings.ContainsRune(r.Wildcards, l) {
+			wild++
+		} else {
+			count[l]++
+		}
+	}
+	counts := make([]int, 0, len(count)+wild)
+	for _, c := range count {
+		counts = append(counts, c)
+	}
+
+	var best []int
+	bestRank := -2
+	var spread func(w int)
+	spread = func(w int) {
+		if w == 0 {
+			c := slices.Clone(counts)
+			slices.SortFunc(c, func(a, b int) int { return b - a })
+			rank := r.rankOf(c)
+			if cmp.Or(cmp.Compare(rank, bestRank), slices.Compare(c, best)) > 0 {
+				best, bestRank = c, rank
+			}
+			return
+		}
+		// A wildcard joins a group of same cards, or starts a new one.
+		for i := range counts {
+			counts[i]++
+			spread(w - 1)
+			counts[i]--
+		}
+		counts = append(counts, 1)
+		spread(w - 1)
+		counts = counts[:len(counts)-1]
+	}
+	spread(wild)
+	return best, bestRank
+}
+
+func (r *Ruleset) parseHand(line string) (*Hand, error) {
+	parts := strings.Fields(line)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("must have exactly two parts")
+	}
+	labels := []rune(parts[0])
+	if len(labels) != r.HandSize {
+		return nil, fmt.Errorf("must have exactly %d cards", r.HandSize)
+	}
+	cards := make([]int, len(labels))
+	for i, l := range labels {
+		if cards[i] = strings.IndexRune(r.Order, l); cards[i] == -1 {
+			return nil, fmt.Errorf("unknown card %q", l)
+		}
+	}
+	if r.TieBreak == TieSorted {
+		slices.SortFunc(cards, func(a, b int) int { return b - a })
+	}
+	bid, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hand{cards: cards, bid: bid}
+	h.category, h.rank = r.categorize(labels)
+	return h, nil
+}
@@ -0,0 +1,168 @@
+package day07
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kanna5/advent_of_code/common/registry"
+)
+
+const example = `32T3K 765
+T55J5 684
+KK677 28
+KTJJT 220
+QQQJA 483
+`
+
+func TestSolution(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  registry.Options
+		input string
+		part  int
+		want  string
+		err   string
+	}{
+		{name: "example", input: example, part: 1, want: "6440"},
+		{name: "example", input: example, part: 2, want: "5905"},
+		// Without J, which is only checked in part 2 as the wildcard.
+		{name: "no J", opts: registry.Options{"order": "23456789TQKA"}, input: "32T3K 765\nKK677 28\n", part: 1, want: "821"},
+		{name: "no J", opts: registry.Options{"order": "23456789TQKA"}, input: "32T3K 765\nKK677 28\n", part: 2, err: "wildcard 'J' is not an ordered card"},
+		{name: "six cards", opts: registry.Options{"size": "6"}, input: "22233A 1\nJJJJJJ 10\n", part: 2, want: "21"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sol{}
+			if err := s.SetOptions(tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			s.WithInput(strings.NewReader(tt.input))
+			got, err := s.solve(tt.part)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("part %d: got error %v, expected %q", tt.part, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("part %d: got %q, expected %q", tt.part, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRulesetWith(t *testing.T) {
+	tests := []struct {
+		name string
+		part int
+		opts registry.Options
+		want Ruleset
+		err  string
+	}{
+		{name: "default", part: 1, want: defaultRules[1]},
+		{
+			name: "size",
+			part: 2,
+			opts: registry.Options{"size": "6"},
+			want: Ruleset{Order: "J23456789TQKA", Wildcards: "J", HandSize: 6},
+		},
+		{
+			name: "wildcards",
+			part: 1,
+			opts: registry.Options{"wildcards": "J2"},
+			want: Ruleset{Order: "23456789TJQKA", Wildcards: "J2", HandSize: 5},
+		},
+		{
+			name: "categories",
+			part: 1,
+			opts: registry.Options{"categories": "5,41,32", "tiebreak": "sorted"},
+			want: Ruleset{Order: "23456789TJQKA", HandSize: 5, Categories: [][]int{{5}, {4, 1}, {3, 2}}, TieBreak: TieSorted},
+		},
+		{name: "unknown option", part: 1, opts: registry.Options{"jokers": "J"}, err: "jokers"},
+		{name: "size zero", part: 1, opts: registry.Options{"size": "0"}, err: `invalid hand size "0"`},
+		{name: "card twice", part: 1, opts: registry.Options{"order": "23456789TJQKAA"}, err: "card 'A' is ordered twice"},
+		{name: "unknown wildcard", part: 1, opts: registry.Options{"wildcards": "X"}, err: "wildcard 'X' is not an ordered card"},
+		{name: "category digit", part: 1, opts: registry.Options{"categories": "5,401"}, err: `invalid hand category "401"`},
+		{name: "category sum", part: 1, opts: registry.Options{"categories": "5,41", "size": "6"}, err: "invalid hand category [5] for hands of 6 cards"},
+		{name: "category order", part: 1, opts: registry.Options{"categories": "23"}, err: "invalid hand category [2 3]"},
+		{name: "tiebreak", part: 1, opts: registry.Options{"tiebreak": "random"}, err: `invalid tie-break "random"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := defaultRules[tt.part].With(tt.opts)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, expected %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Order != tt.want.Order || got.Wildcards != tt.want.Wildcards || got.HandSize != tt.want.HandSize ||
+				!slices.EqualFunc(got.Categories, tt.want.Categories, slices.Equal) || got.TieBreak != tt.want.TieBreak {
+				t.Errorf("got %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		part     int
+		opts     registry.Options
+		hand     string
+		category []int
+		rank     int
+	}{
+		{name: "one pair", part: 1, hand: "32T3K", category: []int{2, 1, 1, 1}, rank: -1},
+		{name: "J is not wild", part: 1, hand: "KTJJT", category: []int{2, 2, 1}, rank: -1},
+		{name: "J is wild", part: 2, hand: "KTJJT", category: []int{4, 1}, rank: -1},
+		{name: "all wild", part: 2, hand: "JJJJJ", category: []int{5}, rank: -1},
+		{name: "two wildcards", part: 2, opts: registry.Options{"wildcards": "J2"}, hand: "J2K2Q", category: []int{4, 1}, rank: -1},
+		{name: "six cards", part: 2, opts: registry.Options{"size": "6"}, hand: "AAAKKJ", category: []int{4, 2}, rank: -1},
+		{name: "six wildcards", part: 2, opts: registry.Options{"size": "6"}, hand: "JJJJJJ", category: []int{6}, rank: -1},
+		{
+			name:     "ranked category",
+			part:     1,
+			opts:     registry.Options{"categories": "5,41,32,311"},
+			hand:     "22233",
+			category: []int{3, 2},
+			rank:     1,
+		},
+		{
+			name:     "no category",
+			part:     1,
+			opts:     registry.Options{"categories": "5,41,32,311"},
+			hand:     "22334",
+			category: []int{2, 2, 1},
+			rank:     -1,
+		},
+		{
+			// Wildcards make the strongest category, not the most same cards.
+			name:     "full house beats five",
+			part:     2,
+			opts:     registry.Options{"categories": "32,5"},
+			hand:     "JJJJ2",
+			category: []int{3, 2},
+			rank:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := defaultRules[tt.part].With(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			category, rank := r.categorize([]rune(tt.hand))
+			if !slices.Equal(category, tt.category) || rank != tt.rank {
+				t.Errorf("got %v (rank %d), expected %v (rank %d)", category, rank, tt.category, tt.rank)
+			}
+		})
+	}
+}
@@ -1,16 +1,26 @@
 // Solution for https://adventofcode.com/2023/day/8
 package day08
 
+// Part 2: Each ghost loops over the same (node, instruction index) states after
+// a while. The first step where all are at Z nodes is either before the last
+// ghost enters its loop, or found with CRT from the Z nodes in the loops. Set
+// DIAGNOSE to see the loops, and whether the LCM of the first Z steps is enough.
+
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -101,25 +111,139 @@ outer:
 	return strconv.FormatInt(steps, 10), nil
 }
 
-func findSteps2(map_ *Map, start *Node) (int64, error) {
-	var steps int64
+// Ghost is the walk from a start node, which eventually loops over the same
+// (node, instruction index) states. Steps are counted from the start.
+type Ghost struct {
+	start  string
+	prefix int64   // Steps before entering the loop
+	period int64   // Length of the loop
+	hits   []int64 // Steps at Z nodes before the end of the first loop
+}
+
+func walkGhost(map_ *Map, start *Node) (*Ghost, error) {
+	type state struct {
+		node  *Node
+		instr int
+	}
+	n := len(map_.instructions)
+	seen := map[state]int64{}
+	g := &Ghost{start: start.id}
+
 	p := start
-	for {
-		for _, i := range map_.instructions {
-			if i == 'L' {
-				p = map_.nodes[p.l]
-			} else {
-				p = map_.nodes[p.r]
-			}
-			if p == nil {
-				return 0, fmt.Errorf("missing node")
-			}
-			steps++
-			if strings.HasSuffix(p.id, "Z") {
-				return steps, nil
-			}
+	for steps := int64(0); ; steps++ {
+		st := state{p, int(steps % int64(n))}
+		if first, ok := seen[st]; ok {
+			g.prefix, g.period = first, steps-first
+			return g, nil
+		}
+		seen[st] = steps
+		if strings.HasSuffix(p.id, "Z") {
+			g.hits = append(g.hits, steps)
+		}
+
+		if map_.instructions[st.instr] == 'L' {
+			p = map_.nodes[p.l]
+		} else {
+			p = map_.nodes[p.r]
+		}
+		if p == nil {
+			return nil, fmt.Errorf("missing node")
+		}
+	}
+}
+
+// At tells whether the ghost is at a Z node after the given steps.
+func (g *Ghost) At(steps int64) bool {
+	if steps >= g.prefix {
+		steps = g.prefix + (steps-g.prefix)%g.period
+	}
+	_, found := slices.BinarySearch(g.hits, steps)
+	return found
+}
+
+// loopHits returns the offsets in the loop of the Z nodes, relative to the start.
+func (g *Ghost) loopHits() []int64 {
+	i, _ := slices.BinarySearch(g.hits, g.prefix)
+	return g.hits[i:]
+}
+
+// periodic tells whether the ghost is at a Z node exactly every n steps, where
+// n is the steps to reach the first one. Finding the LCM of these is enough if
+// every ghost is periodic.
+func (g *Ghost) periodic() bool {
+	if len(g.hits) == 0 || g.hits[0] == 0 {
+		return false
+	}
+	first := g.hits[0]
+	if g.period%first != 0 {
+		return false
+	}
+	for steps := int64(1); steps < g.prefix+g.period; steps++ {
+		if g.At(steps) != (steps%first == 0) {
+			return false
 		}
 	}
+	return true
+}
+
+// maxCombinations limits the systems of congruences to solve, one for each
+// combination of Z nodes in the loops.
+const maxCombinations = 1 << 20
+
+// findSteps2 finds the first step where every ghost is at a Z node.
+func findSteps2(ghosts []*Ghost) (int64, error) {
+	// Some ghost is still before its loop.
+	var start int64
+	for _, g := range ghosts {
+		start = max(start, g.prefix)
+	}
+	for steps := int64(1); steps < start; steps++ {
+		if !slices.ContainsFunc(ghosts, func(g *Ghost) bool { return !g.At(steps) }) {
+			return steps, nil
+		}
+	}
+
+	// Every ghost is in its loop.
+	combinations := 1
+	periods := make([]int64, len(ghosts))
+	for i, g := range ghosts {
+		combinations *= len(g.loopHits())
+		if combinations == 0 {
+			return 0, fmt.Errorf("the ghost starting at %s never reaches a Z node repeatedly", g.start)
+		}
+		if combinations > maxCombinations {
+			return 0, fmt.Errorf("too many combinations of Z nodes in the loops")
+		}
+		periods[i] = g.period
+	}
+
+	var best int64 = -1
+	residues := make([]int64, len(ghosts))
+	for n := range combinations {
+		c := n
+		for i, g := range ghosts {
+			hits := g.loopHits()
+			residues[i] = hits[c%len(hits)] % g.period
+			c /= len(hits)
+		}
+		x, lcm, err := lib.Crt(residues, periods)
+		if errors.Is(err, lib.ErrNoSolution) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if x < start {
+			x += (start - x + lcm - 1) / lcm * lcm
+		}
+		if best == -1 || x < best {
+			best = x
+		}
+	}
+	if best == -1 {
+		return 0, fmt.Errorf("the ghosts are never at Z nodes at the same time")
+	}
+	return best, nil
 }
 
 func (s *sol) SolvePart2() (string, error) {
@@ -128,32 +252,60 @@ func (s *sol) SolvePart2() (string, error) {
 		return "", err
 	}
 
-	startPos := make([]*Node, 0, len(map_.nodes))
 	// look for all nodes ending with A
+	ghosts := []*Ghost{}
 	for id, node := range map_.nodes {
 		if strings.HasSuffix(id, "A") {
-			startPos = append(startPos, node)
+			g, err := walkGhost(map_, node)
+			if err != nil {
+				return "", err
+			}
+			ghosts = append(ghosts, g)
 		}
 	}
-	allSteps := make([]int64, 0, len(startPos))
-
-	for _, node := range startPos {
-		steps, err := findSteps2(map_, node)
-		if err != nil {
-			return "", err
-		}
-		allSteps = append(allSteps, steps)
+	slices.SortFunc(ghosts, func(a, b *Ghost) int { return strings.Compare(a.start, b.start) })
+	if len(ghosts) == 0 {
+		return "", fmt.Errorf("no nodes ending with A")
 	}
 
-	result := lib.LcmSeq(allSteps)
+	if len(os.Getenv("DIAGNOSE")) != 0 {
+		diagnose(ghosts)
+	}
+	result, err := findSteps2(ghosts)
+	if err != nil {
+		return "", err
+	}
 	return strconv.FormatInt(result, 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+// diagnose logs the loop of each ghost, and whether the LCM of the steps to the
+// first Z nodes gives the answer.
+func diagnose(ghosts []*Ghost) {
+	firsts := []int64{}
+	periodic := true
+	for _, g := range ghosts {
+		log.Printf("%s: loop of %d steps after %d, at Z after %v", g.start, g.period, g.prefix, g.hits)
+		if !g.periodic() {
+			periodic = false
+			log.Printf("%s: not at Z every %v steps", g.start, g.hits)
+			continue
+		}
+		firsts = append(firsts, g.hits[0])
+	}
+	if periodic {
+		log.Printf("the LCM shortcut holds: LCM%v = %d", firsts, lib.LcmSeq(firsts))
+	} else {
+		log.Printf("the LCM shortcut does not hold")
+	}
+}
+
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[8] = &sol{}
+	registry.Register(solutions.Year, 8, &sol{}, registry.Meta{
+		Title: "Haunted Wasteland",
+		Tags:  []registry.Tag{registry.Graph, registry.Math},
+	})
 }
@@ -0,0 +1,135 @@
+package day08
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const example = `LR
+
+11A = (11B, XXX)
+11B = (XXX, 11Z)
+11Z = (11B, XXX)
+22A = (22B, XXX)
+22B = (22C, 22C)
+22C = (22Z, 22Z)
+22Z = (22B, 22B)
+XXX = (XXX, XXX)
+`
+
+// ghosts builds a map where each ghost goes along its own path, given as a
+// prefix and a loop where 'z' marks the steps at Z nodes. The first step of the
+// prefix is the start node.
+func ghosts(paths ...[2]string) string {
+	sb := strings.Builder{}
+	sb.WriteString("L\n\n")
+	for g, p := range paths {
+		path := p[0] + p[1]
+		name := func(i int) string {
+			switch {
+			case i == 0:
+				return fmt.Sprintf("%c00A", 'a'+g)
+			case path[i] == 'z':
+				return fmt.Sprintf("%c%02dZ", 'a'+g, i)
+			}
+			return fmt.Sprintf("%c%02dX", 'a'+g, i)
+		}
+		for i := range path {
+			next := i + 1
+			if next == len(path) {
+				next = len(p[0])
+			}
+			fmt.Fprintf(&sb, "%s = (%s, %s)\n", name(i), name(next), name(next))
+		}
+	}
+	return sb.String()
+}
+
+// bruteForce walks every ghost together until they are all at Z nodes, or
+// returns -1.
+func bruteForce(t *testing.T, input string) int64 {
+	t.Helper()
+	map_, err := readInput(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := []*Node{}
+	for id, node := range map_.nodes {
+		if strings.HasSuffix(id, "A") {
+			ps = append(ps, node)
+		}
+	}
+	for steps := int64(1); steps <= 10000; steps++ {
+		done := true
+		for i, p := range ps {
+			if map_.instructions[(steps-1)%int64(len(map_.instructions))] == 'L' {
+				ps[i] = map_.nodes[p.l]
+			} else {
+				ps[i] = map_.nodes[p.r]
+			}
+			done = done && strings.HasSuffix(ps[i].id, "Z")
+		}
+		if done {
+			return steps
+		}
+	}
+	return -1
+}
+
+func TestSolvePart2(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"example", example},
+		{"offsets", ghosts([2]string{"...", "z.."}, [2]string{".", "..z.z"})},
+		{"several hits", ghosts([2]string{"..", "z..z...."}, [2]string{".", "..z..z.z."})},
+		{"hit in the prefix", ghosts([2]string{"...z....", "..z"}, [2]string{".", "..z"})},
+		// The first combination of hits has no solution: 1 mod 4 and 0 mod 2.
+		{"no solution skipped", ghosts([2]string{".", "zz.."}, [2]string{".", ".z"})},
+		{"three ghosts", ghosts([2]string{"....", "z.z.."}, [2]string{"..", ".z."}, [2]string{".z", "...z..z"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := bruteForce(t, tt.input)
+			if want == -1 {
+				t.Fatal("the ghosts are never at Z nodes together within 10000 steps")
+			}
+			s := &sol{}
+			s.WithInput(strings.NewReader(tt.input))
+			got, err := s.SolvePart2()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != strconv.FormatInt(want, 10) {
+				t.Errorf("got %q, expected %d", got, want)
+			}
+		})
+	}
+}
+
+func TestSolvePart2Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"never together", ghosts([2]string{".", "z..."}, [2]string{".", ".z"}), "never at Z nodes at the same time"},
+		{"hit in the prefix only", ghosts([2]string{"..z", ".."}, [2]string{".", "z."}), "never reaches a Z node repeatedly"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if bruteForce(t, tt.input) != -1 {
+				t.Fatal("the ghosts are at Z nodes together")
+			}
+			s := &sol{}
+			s.WithInput(strings.NewReader(tt.input))
+			_, err := s.SolvePart2()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, expected %q", err, tt.want)
+			}
+		})
+	}
+}
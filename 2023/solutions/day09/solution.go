@@ -2,13 +2,14 @@
 package day09
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"iter"
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -60,19 +61,34 @@ func extrapolate(seq []int64, forward bool) (int64, error) {
 	return val, nil
 }
 
+func iterInput(input io.Reader) iter.Seq2[[]int64, error] {
+	return func(yield func([]int64, error) bool) {
+		for line, err := range lib.Lines(input) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			row, err := lib.StrToIntSlice[int64](line)
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to parse %#v: %#v", line, err))
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
 func (s *sol) solve(forward bool) (string, error) {
 	var sum int64
-	sc := bufio.NewScanner(s.input)
-	for sc.Scan() {
-		line := sc.Text()
-		row, err := lib.StrToIntSlice[int64](line)
+	for row, err := range iterInput(s.input) {
 		if err != nil {
-			return "", fmt.Errorf("failed to parse %#v: %#v", line, err)
+			return "", err
 		}
 		extrapolated, err := extrapolate(row, forward)
 		if err != nil {
-			return "", fmt.Errorf("unable to find extrapolated values for %#v: %#v", line, err)
-
+			return "", fmt.Errorf("unable to find extrapolated values for %v: %#v", row, err)
 		}
 		sum += extrapolated
 	}
@@ -88,11 +104,13 @@ func (s *sol) SolvePart2() (string, error) {
 	return s.solve(false)
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[9] = &sol{}
+	registry.Register(solutions.Year, 9, &sol{}, registry.Meta{
+		Title: "Mirage Maintenance",
+		Tags:  []registry.Tag{registry.Math},
+	})
 }
@@ -0,0 +1,87 @@
+package day10
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kanna5/advent_of_code/2023/lib"
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+// Box-drawing glyphs of the pipes of the main loop.
+var loopGlyphs = map[rune]rune{'|': '┃', '-': '━', 'L': '┗', 'J': '┛', '7': '┓', 'F': '┏'}
+
+// farthest returns the position of the loop that is the farthest from the
+// start, following the loop.
+func (m *Map) farthest(loop map[Coord]Visit) Coord {
+	c := m.start
+	for range len(loop) / 2 {
+		d := directions[loop[c].out]
+		c = Coord{c.x + d[0], c.y + d[1]}
+	}
+	return c
+}
+
+// drawMap draws the main loop in heavy lines, and the blocks inside and
+// outside of it in different colors. Blocks off the loop, pipes as well as the
+// ground, are marked with I or O so that the sides can be told apart without
+// colors, and the blocks inside counted.
+func drawMap(lines []string, loop map[Coord]Visit, inside lib.Set[Coord], start, far Coord) *viz.Grid {
+	g := viz.GridFromLines(lines)
+	g.Caption = fmt.Sprintf("loop %d, farthest %d, inside %d", len(loop), len(loop)/2, len(inside))
+	for y, line := range lines {
+		for x, r := range []rune(line) {
+			c := Coord{x, y}
+			cell := g.At(x, y)
+			_, inLoop := loop[c]
+			isInside := inside.Has(c)
+			switch {
+			case inLoop:
+				cell.Ch = loopGlyphs[r]
+				cell.FG = viz.Black
+				cell.BG = viz.Orange
+			case isInside:
+				cell.Ch = 'I'
+				cell.FG = viz.White
+				cell.BG = viz.Green
+			default:
+				cell.Ch = 'O'
+				cell.FG = viz.White
+				cell.BG = viz.Blue
+			}
+		}
+	}
+
+	s := g.At(start.x, start.y)
+	s.Ch, s.FG, s.BG = 'S', viz.White, viz.Red
+	f := g.At(far.x, far.y)
+	f.Ch, f.FG, f.BG = '*', viz.Black, viz.Yellow
+	return g
+}
+
+// Visualize draws the main loop, the start and farthest point, and the blocks
+// inside and outside of the loop.
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	input, err := io.ReadAll(s.input)
+	if err != nil {
+		return nil, err
+	}
+	map_, err := readMap(bytes.NewReader(input))
+	if err != nil {
+		return nil, err
+	}
+	loop, err := map_.mainLoop()
+	if err != nil {
+		return nil, err
+	}
+	inside, err := map_.enclosed(loop)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(input), "\n"), "\n")
+	g := drawMap(lines, loop, inside, map_.start, map_.farthest(loop))
+	return []viz.Figure{{Name: "loop", Scene: g}}, nil
+}
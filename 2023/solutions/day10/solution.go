@@ -10,6 +10,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -112,23 +113,18 @@ func findLoop(map_ *Map, startDir Direction) map[Coord]Visit {
 	}
 }
 
-func (s *sol) SolvePart2() (string, error) {
-	map_, err := readMap(s.input)
-	if err != nil {
-		return "", err
-	}
-
-	// Find the loop
-	var loop map[Coord]Visit
+// mainLoop finds the loop going through the starting point.
+func (m *Map) mainLoop() (map[Coord]Visit, error) {
 	for _, dir := range []Direction{Up, Right, Down, Left} {
-		if loop = findLoop(map_, dir); loop != nil {
-			break
+		if loop := findLoop(m, dir); loop != nil {
+			return loop, nil
 		}
 	}
-	if loop == nil {
-		return "", fmt.Errorf("no loop found")
-	}
+	return nil, fmt.Errorf("no loop found")
+}
 
+// enclosed returns the blocks enclosed by the loop.
+func (m *Map) enclosed(loop map[Coord]Visit) (lib.Set[Coord], error) {
 	// Gather inside and outside blocks
 	sideDirs := [...]Direction{Left, Right}
 	sides := [...]lib.Set[Coord]{
@@ -138,7 +134,7 @@ func (s *sol) SolvePart2() (string, error) {
 	isOutside := [...]bool{false, false}
 
 	for _, v := range loop {
-		cur := map_.cursor(v.c.x, v.c.y)
+		cur := m.cursor(v.c.x, v.c.y)
 		for i, dir := range sideDirs {
 			for _, neighbor := range [...]*MapCursor{
 				cur.move(v.in.Rot(dir)), cur.move(v.out.Rot(dir)), // could be the same
@@ -164,7 +160,7 @@ func (s *sol) SolvePart2() (string, error) {
 		queue = slices.AppendSeq(queue, maps.Keys(sides[side]))
 	Loop:
 		for i := 0; i < len(queue); i++ {
-			cur := map_.cursor(queue[i].x, queue[i].y)
+			cur := m.cursor(queue[i].x, queue[i].y)
 			for _, dir := range []Direction{Up, Right, Down, Left} {
 				neighbor := cur.move(dir)
 				if neighbor == nil {
@@ -183,17 +179,36 @@ func (s *sol) SolvePart2() (string, error) {
 
 	for side, out := range isOutside {
 		if !out {
-			return strconv.FormatInt(int64(len(sides[side])), 10), nil
+			return sides[side], nil
 		}
 	}
-	return "", fmt.Errorf("failed to find the inside")
+	return nil, fmt.Errorf("failed to find the inside")
+}
+
+func (s *sol) SolvePart2() (string, error) {
+	map_, err := readMap(s.input)
+	if err != nil {
+		return "", err
+	}
+
+	loop, err := map_.mainLoop()
+	if err != nil {
+		return "", err
+	}
+	inside, err := map_.enclosed(loop)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(int64(len(inside)), 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[10] = &sol{}
+	registry.Register(solutions.Year, 10, &sol{}, registry.Meta{
+		Title: "Pipe Maze",
+		Tags:  []registry.Tag{registry.Grid},
+	})
 }
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -102,11 +103,13 @@ func (s *sol) SolvePart2() (string, error) {
 	return s.solve(999_999)
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[11] = &sol{}
+	registry.Register(solutions.Year, 11, &sol{}, registry.Meta{
+		Title: "Cosmic Expansion",
+		Tags:  []registry.Tag{registry.Grid, registry.Math},
+	})
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -121,11 +122,13 @@ func (s *sol) SolvePart2() (string, error) {
 	return s.solve(true)
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[12] = &sol{}
+	registry.Register(solutions.Year, 12, &sol{}, registry.Meta{
+		Title: "Hot Springs",
+		Tags:  []registry.Tag{registry.DP},
+	})
 }
@@ -2,12 +2,13 @@
 package day13
 
 import (
-	"bufio"
 	"io"
 	"strconv"
 	"strings"
 
+	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -46,25 +47,10 @@ type scoreFn func([]string) int
 
 func (s *sol) solve(score scoreFn) (string, error) {
 	sum := 0
-	lines := []string{}
-
-	sc := bufio.NewScanner(s.input)
-	for sc.Scan() {
-		line := sc.Text()
-		if len(line) == 0 {
-			if len(lines) > 0 {
-				sum += score(lines)
-				lines = []string{}
-				continue
-			}
+	for lines, err := range lib.Blocks(s.input) {
+		if err != nil {
+			return "", err
 		}
-		lines = append(lines, line)
-	}
-	if err := sc.Err(); err != nil {
-		return "", err
-	}
-
-	if len(lines) > 0 {
 		sum += score(lines)
 	}
 	return strconv.FormatInt(int64(sum), 10), nil
@@ -131,11 +117,13 @@ func (s *sol) SolvePart2() (string, error) {
 	return s.solve(score)
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[13] = &sol{}
+	registry.Register(solutions.Year, 13, &sol{}, registry.Meta{
+		Title: "Point of Incidence",
+		Tags:  []registry.Tag{registry.Grid},
+	})
 }
@@ -0,0 +1,63 @@
+package day14
+
+import (
+	"fmt"
+
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+func drawMap(m Map, caption string) *viz.Grid {
+	g := viz.NewGrid(m.Width(), m.Height())
+	g.Caption = caption
+	for y := range m {
+		for x, c := range m[y] {
+			cell := g.At(x, y)
+			cell.Ch = rune(c)
+			switch c {
+			case RoundedRock:
+				cell.FG = viz.Orange
+			case CubeRock:
+				cell.FG = viz.Gray
+			}
+		}
+	}
+	return g
+}
+
+// Visualize animates the tilts. In part 2, the spin cycles are shown up to and
+// through the first repeated state, and the frames where the loop starts and
+// repeats are highlighted.
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	map_, err := readMap(s.input)
+	if err != nil {
+		return nil, err
+	}
+
+	anim := &viz.Animation{Delay: 5}
+	addFrame := func(caption string) {
+		anim.Frames = append(anim.Frames, drawMap(map_, fmt.Sprintf("#%d %s load %d", len(anim.Frames), caption, map_.Load())))
+	}
+	addFrame("start")
+
+	if part == 1 {
+		map_.TiltNorth()
+		addFrame("north")
+		anim.Delay = 100
+		return []viz.Figure{{Name: "tilt", Scene: anim}}, nil
+	}
+
+	_, loopBase, loopLength := spinUntilLoop(map_, func(cycle, tilt int) {
+		addFrame(fmt.Sprintf("cycle %d %s", cycle, tilts[tilt].name))
+	})
+
+	// Frame 0 is the initial state, and each cycle adds 4 frames.
+	begin, repeat := loopBase*len(tilts), (loopBase+loopLength)*len(tilts)
+	anim.Delays = make([]int, len(anim.Frames))
+	for _, i := range []int{begin, repeat} {
+		anim.Frames[i].Highlight = viz.Yellow
+		anim.Delays[i] = 150
+	}
+	anim.Frames[begin].Caption += fmt.Sprintf(" - loop of %d begins", loopLength)
+	anim.Frames[repeat].Caption += " - repeats"
+	return []viz.Figure{{Name: "cycles", Scene: anim}}, nil
+}
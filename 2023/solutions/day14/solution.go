@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -22,43 +23,56 @@ func (s *sol) SolvePart1() (string, error) {
 	return strconv.FormatInt(int64(map_.Load()), 10), nil
 }
 
-func (s *sol) SolvePart2() (string, error) {
-	map_, err := readMap(s.input)
-	if err != nil {
-		return "", err
-	}
-
-	cycle := func() {
-		map_.TiltNorth()
-		map_.TiltWest()
-		map_.TiltSouth()
-		map_.TiltEast()
-	}
+// Tilt directions, in the order of a spin cycle.
+var tilts = [4]struct {
+	name string
+	tilt func(Map)
+}{
+	{"north", Map.TiltNorth},
+	{"west", Map.TiltWest},
+	{"south", Map.TiltSouth},
+	{"east", Map.TiltEast},
+}
 
-	var loopBase, loopLength int
+// spinUntilLoop runs spin cycles until the map repeats a previous state. It
+// returns the load after each cycle, the cycle at which the loop starts, and
+// the length of the loop. If onTilt is not nil, it is called after each tilt.
+func spinUntilLoop(map_ Map, onTilt func(cycle, tilt int)) (loads []int, loopBase, loopLength int) {
 	cache := map[string]int{}
-	loads := []int{}
 	for i := 1; ; i++ {
-		cycle()
+		for t := range tilts {
+			tilts[t].tilt(map_)
+			if onTilt != nil {
+				onTilt(i, t)
+			}
+		}
 		key := map_.String()
 		if first, ok := cache[key]; ok {
-			loopBase = first
-			loopLength = i - loopBase
-			break
+			return loads, first, i - first
 		}
-		load := map_.Load()
-		loads = append(loads, load)
+		loads = append(loads, map_.Load())
 		cache[key] = i
 	}
+}
+
+func (s *sol) SolvePart2() (string, error) {
+	map_, err := readMap(s.input)
+	if err != nil {
+		return "", err
+	}
+
+	loads, loopBase, loopLength := spinUntilLoop(map_, nil)
 	offset := (1_000_000_000 - loopBase) % loopLength
 	return strconv.FormatInt(int64(loads[loopBase-1+offset]), 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[14] = &sol{}
+	registry.Register(solutions.Year, 14, &sol{}, registry.Meta{
+		Title: "Parabolic Reflector Dish",
+		Tags:  []registry.Tag{registry.Grid, registry.Simulation},
+	})
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -70,11 +71,13 @@ func (s *sol) SolvePart2() (string, error) {
 	return strconv.FormatInt(int64(sum), 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[15] = &sol{}
+	registry.Register(solutions.Year, 15, &sol{}, registry.Meta{
+		Title: "Lens Library",
+		Tags:  []registry.Tag{registry.Simulation},
+	})
 }
@@ -0,0 +1,148 @@
+package day16
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+// Colors of beams by direction. Cells crossed in several directions get a mix.
+var beamColors = map[Direction]color.RGBA{
+	Up:    viz.Red,
+	Right: viz.Orange,
+	Down:  viz.Green,
+	Left:  viz.Blue,
+}
+
+func beamColor(d Direction) color.RGBA {
+	comps := d.Components()
+	ret := beamColors[comps[0]]
+	for i, c := range comps[1:] {
+		ret = viz.Mix(ret, beamColors[c], 1/float64(i+2))
+	}
+	return ret
+}
+
+func beamGlyph(d Direction) rune {
+	switch d {
+	case Up:
+		return '↑'
+	case Right:
+		return '→'
+	case Down:
+		return '↓'
+	case Left:
+		return '←'
+	case Left | Right:
+		return '↔'
+	case Up | Down:
+		return '↕'
+	}
+	return '+'
+}
+
+// drawBeams draws the light coming out of each cell, colored by direction.
+// Mirrors and splitters are drawn in dark gray if they are not lit.
+func drawBeams(m Map, caption string) *viz.Grid {
+	g := viz.NewGrid(len(m[0]), len(m))
+	g.Caption = caption
+	for y := range m {
+		for x, c := range m[y] {
+			cell := g.At(x, y)
+			cell.Ch = rune(c.CellType)
+			switch {
+			case c.lightOut != 0 && c.CellType == Empty:
+				cell.Ch = beamGlyph(c.lightOut)
+				cell.FG = beamColor(c.lightOut)
+			case c.lightOut != 0:
+				cell.FG = viz.Black
+				cell.BG = viz.Mix(beamColor(c.lightOut), viz.Black, 0.3)
+			case c.CellType != Empty:
+				cell.FG = viz.Gray
+				cell.BG = color.RGBA{0x42, 0x42, 0x42, 0xff}
+			}
+		}
+	}
+	return g
+}
+
+// drawHeatmap shows, for each cell, how many entry points energize it. The
+// entry points are drawn around the map, colored by the number of cells they
+// energize, and the best one is highlighted.
+func drawHeatmap(m Map) (*viz.Grid, Entry) {
+	w, h := len(m[0]), len(m)
+	hits := make([][]int, h)
+	for y := range hits {
+		hits[y] = make([]int, w)
+	}
+
+	entries := m.entries()
+	counts := make([]int, len(entries))
+	best := 0
+	for i, e := range entries {
+		lit := m.inputLight(e.Coordinate, e.Direction)
+		counts[i] = lit.countEnergized()
+		if counts[i] > counts[best] {
+			best = i
+		}
+		for y := range lit {
+			for x := range lit[y] {
+				if lit[y][x].lightOut != 0 {
+					hits[y][x]++
+				}
+			}
+		}
+	}
+
+	// The map is at (1, 1), surrounded by entry points.
+	g := viz.NewGrid(w+2, h+2)
+	g.Caption = fmt.Sprintf("best entry (%d, %d) %c energizes %d", entries[best].x, entries[best].y, beamGlyph(entries[best].Direction), counts[best])
+	for y := range m {
+		for x, c := range m[y] {
+			cell := g.At(x+1, y+1)
+			cell.Ch = rune(c.CellType)
+			cell.BG = viz.Heat(float64(hits[y][x]) / float64(len(entries)))
+			cell.FG = viz.Black
+		}
+	}
+	for i, e := range entries {
+		pos := e.move(opposite(e.Direction))
+		cell := g.At(pos.x+1, pos.y+1)
+		cell.Ch = beamGlyph(e.Direction)
+		cell.BG = viz.Heat(float64(counts[i]) / float64(counts[best]))
+		cell.FG = viz.Black
+		if i == best {
+			cell.BG, cell.FG = viz.White, viz.Black
+			cell.Ch = '*'
+		}
+	}
+	return g, entries[best]
+}
+
+func opposite(d Direction) Direction {
+	return map[Direction]Direction{Up: Down, Down: Up, Left: Right, Right: Left}[d]
+}
+
+// Visualize draws the beams from the top-left corner in part 1. In part 2 it
+// draws the heatmap of all entry points, and the beams of the best one.
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	map_, err := readMap(s.input)
+	if err != nil {
+		return nil, err
+	}
+
+	if part == 1 {
+		lit := map_.inputLight(Coordinate{0, 0}, Right)
+		return []viz.Figure{
+			{Name: "beams", Scene: drawBeams(lit, fmt.Sprintf("energized %d", lit.countEnergized()))},
+		}, nil
+	}
+
+	heatmap, best := drawHeatmap(map_)
+	lit := map_.inputLight(best.Coordinate, best.Direction)
+	return []viz.Figure{
+		{Name: "heatmap", Scene: heatmap},
+		{Name: "best", Scene: drawBeams(lit, heatmap.Caption)},
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -29,24 +30,19 @@ func (s *sol) SolvePart2() (string, error) {
 	}
 
 	maxN := 0
-	for y := range map_ {
-		nEnergized1 := map_.inputLight(Coordinate{0, y}, Right).countEnergized()
-		nEnergized2 := map_.inputLight(Coordinate{len(map_[0]) - 1, y}, Left).countEnergized()
-		maxN = max(maxN, nEnergized1, nEnergized2)
-	}
-	for x := range map_[0] {
-		nEnergized1 := map_.inputLight(Coordinate{x, 0}, Down).countEnergized()
-		nEnergized2 := map_.inputLight(Coordinate{x, len(map_) - 1}, Up).countEnergized()
-		maxN = max(maxN, nEnergized1, nEnergized2)
+	for _, e := range map_.entries() {
+		maxN = max(maxN, map_.inputLight(e.Coordinate, e.Direction).countEnergized())
 	}
 	return strconv.FormatInt(int64(maxN), 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[16] = &sol{}
+	registry.Register(solutions.Year, 16, &sol{}, registry.Meta{
+		Title: "The Floor Will Be Lava",
+		Tags:  []registry.Tag{registry.Grid, registry.Simulation},
+	})
 }
@@ -192,6 +192,24 @@ func (m Map) clone() Map {
 	return ret
 }
 
+type Entry struct {
+	Coordinate
+	Direction
+}
+
+// entries returns all positions on the edge where light can enter, heading
+// inwards.
+func (m Map) entries() []Entry {
+	ret := make([]Entry, 0, 2*(len(m)+len(m[0])))
+	for y := range m {
+		ret = append(ret, Entry{Coordinate{0, y}, Right}, Entry{Coordinate{len(m[0]) - 1, y}, Left})
+	}
+	for x := range m[0] {
+		ret = append(ret, Entry{Coordinate{x, 0}, Down}, Entry{Coordinate{x, len(m) - 1}, Up})
+	}
+	return ret
+}
+
 func (m Map) inputLight(c Coordinate, d Direction) Map {
 	rMap := m.clone()
 
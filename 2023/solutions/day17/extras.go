@@ -0,0 +1,56 @@
+package day17
+
+import (
+	"fmt"
+
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+var arrows = [...]rune{Up: '↑', Right: '→', Down: '↓', Left: '←'}
+
+// drawPath draws the heat loss of each block in shades of gray, darker is
+// worse, and the path over it. The color of the path goes from yellow to red as
+// the crucible moves further in a straight line.
+func drawPath(m Map, p Path, maxRun int) *viz.Grid {
+	g := viz.NewGrid(len(m[0]), len(m))
+	g.Caption = fmt.Sprintf("loss %d in %d steps", p.Loss(), len(p)-1)
+	for y := range m {
+		for x, c := range m[y] {
+			cell := g.At(x, y)
+			cell.Ch = rune('0' + c.loss)
+			cell.BG = viz.Mix(viz.White, viz.Black, float64(c.loss)/12)
+			cell.FG = viz.Black
+			if c.loss >= 5 {
+				cell.FG = viz.White
+			}
+		}
+	}
+	for i, s := range p {
+		cell := g.At(s.x, s.y)
+		cell.FG = viz.Black
+		if i == 0 {
+			cell.BG = viz.Green
+			continue
+		}
+		cell.Ch = arrows[s.Dir]
+		cell.BG = viz.Mix(viz.Yellow, viz.Red, float64(s.Run-1)/float64(max(maxRun-1, 1)))
+	}
+	return g
+}
+
+// Visualize draws the path with the least heat loss over the map.
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	rules, err := defaultRules[part].With(s.opts)
+	if err != nil {
+		return nil, err
+	}
+	map_, err := readMap(s.input)
+	if err != nil {
+		return nil, err
+	}
+	p, err := map_.bestPath(&rules)
+	if err != nil {
+		return nil, err
+	}
+	return []viz.Figure{{Name: "path", Scene: drawPath(map_, p, rules.MaxRun)}}, nil
+}
@@ -0,0 +1,143 @@
+package day17
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kanna5/advent_of_code/common/registry"
+)
+
+var turnNames = map[Turn]string{TurnLeft: "left", TurnRight: "right", TurnBack: "back"}
+
+// Rules of the movement of a crucible.
+type Rules struct {
+	// Blocks to move in a straight line before turning or stopping, and at
+	// most.
+	MinRun, MaxRun int
+	Turns          []Turn
+
+	// Blocks to start and end at. Negative coordinates count from the right
+	// and the bottom of the map, e.g. (-1, -1) is the bottom-right block.
+	Start, End Coordinate
+}
+
+var defaultRules = [...]Rules{
+	1: {MinRun: 1, MaxRun: 3, Turns: []Turn{TurnLeft, TurnRight}, End: Coordinate{-1, -1}},
+	2: {MinRun: 4, MaxRun: 10, Turns: []Turn{TurnLeft, TurnRight}, End: Coordinate{-1, -1}},
+}
+
+var optionKeys = []string{"min", "max", "turns", "start", "end"}
+
+func parseCoordinate(s string) (Coordinate, error) {
+	xs, ys, ok := strings.Cut(s, ",")
+	x, err1 := strconv.Atoi(xs)
+	y, err2 := strconv.Atoi(ys)
+	if !ok || err1 != nil || err2 != nil {
+		return Coordinate{}, fmt.Errorf("invalid coordinate %q, must be x,y", s)
+	}
+	return Coordinate{x, y}, nil
+}
+
+// With returns the rules changed by the options:
+//
+//	min=1                 blocks in a straight line before turning or stopping
+//	max=3                 blocks in a straight line at most
+//	turns=left,right,back allowed turns, can be empty
+//	start=0,0             block to start at
+//	end=-1,-1             block to end at
+func (r Rules) With(opts registry.Options) (Rules, error) {
+	if err := opts.Check(optionKeys...); err != nil {
+		return r, err
+	}
+	for key, run := range map[string]*int{"min": &r.MinRun, "max": &r.MaxRun} {
+		if v, ok := opts[key]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return r, fmt.Errorf("invalid %s run length %q", key, v)
+			}
+			*run = n
+		}
+	}
+	if v, ok := opts["turns"]; ok {
+		r.Turns = nil
+		for name := range strings.SplitSeq(v, ",") {
+			if name == "" {
+				continue
+			}
+			turn := Turn(0)
+			for t, n := range turnNames {
+				if n == name {
+					turn = t
+				}
+			}
+			if turn == 0 {
+				return r, fmt.Errorf("invalid turn %q, can be left, right or back", name)
+			}
+			r.Turns = append(r.Turns, turn)
+		}
+	}
+	for key, c := range map[string]*Coordinate{"start": &r.Start, "end": &r.End} {
+		if v, ok := opts[key]; ok {
+			parsed, err := parseCoordinate(v)
+			if err != nil {
+				return r, err
+			}
+			*c = parsed
+		}
+	}
+	if r.MinRun < 1 || r.MaxRun < r.MinRun || r.MaxRun > 255 {
+		return r, fmt.Errorf("invalid run lengths %d~%d", r.MinRun, r.MaxRun)
+	}
+	return r, nil
+}
+
+// resolve returns a block of the map, counting negative coordinates from the
+// right and the bottom.
+func (m Map) resolve(c Coordinate) (Coordinate, error) {
+	if len(m) > 0 {
+		if c.x < 0 {
+			c.x += len(m[0])
+		}
+		if c.y < 0 {
+			c.y += len(m)
+		}
+	}
+	if !m.Contains(c) {
+		return c, fmt.Errorf("%v is out of the map", c)
+	}
+	return c, nil
+}
+
+// bestPath finds the path with the least loss allowed by the rules, and checks
+// that it is valid.
+func (m Map) bestPath(r *Rules) (Path, error) {
+	start, err := m.resolve(r.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start: %v", err)
+	}
+	end, err := m.resolve(r.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end: %v", err)
+	}
+	if start == end {
+		// The crucible is already there, without moving.
+		return Path{{Coordinate: start}}, nil
+	}
+
+	_, traces := m.findLeastLoss(r, start, end)
+	if traces == nil {
+		return nil, fmt.Errorf("the end is unreachable")
+	}
+	p, err := m.path(traces)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.validate(r); err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+	if p[0].Coordinate != start || p[len(p)-1].Coordinate != end {
+		return nil, fmt.Errorf("invalid path: goes from %v to %v", p[0].Coordinate, p[len(p)-1].Coordinate)
+	}
+	return p, nil
+}
@@ -1,40 +1,63 @@
 // Solution for https://adventofcode.com/2023/day/17
 package day17
 
+// Both parts search the same way, with the rules of movement of their crucible.
+// Options change the rules, e.g. -o min=2 -o max=5 -o turns=left,back.
+
 import (
 	"io"
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
 	input io.Reader
+	opts  registry.Options
 }
 
-func (s *sol) SolvePart1() (string, error) {
-	map_, err := readMap(s.input)
+func (s *sol) solve(part int) (string, error) {
+	rules, err := defaultRules[part].With(s.opts)
 	if err != nil {
 		return "", err
 	}
-	l := map_.findLeastLoss()
-	return strconv.FormatInt(int64(l), 10), nil
-}
-
-func (s *sol) SolvePart2() (string, error) {
 	map_, err := readMap(s.input)
 	if err != nil {
 		return "", err
 	}
-	l := map_.findLeastLoss2()
-	return strconv.FormatInt(int64(l), 10), nil
+	p, err := map_.bestPath(&rules)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(int64(p.Loss()), 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) SolvePart1() (string, error) {
+	return s.solve(1)
+}
+
+func (s *sol) SolvePart2() (string, error) {
+	return s.solve(2)
+}
+
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
+}
+
+// SetOptions changes the rules of both parts, see Rules.With. The values are
+// checked when solving, against the rules of the part.
+func (s *sol) SetOptions(opts registry.Options) error {
+	if err := opts.Check(optionKeys...); err != nil {
+		return err
+	}
+	s.opts = opts
+	return nil
 }
 
 func init() {
-	solutions.Days[17] = &sol{}
+	registry.Register(solutions.Year, 17, &sol{}, registry.Meta{
+		Title: "Clumsy Crucible",
+		Tags:  []registry.Tag{registry.Grid, registry.Graph},
+	})
 }
@@ -0,0 +1,77 @@
+package day17
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kanna5/advent_of_code/common/registry"
+)
+
+const example1 = `2413432311323
+3215453535623
+3255245654254
+3446585845452
+4546657867536
+1438598798454
+4457876987766
+3637877979653
+4654967986887
+4564679986453
+1224686865563
+2546548887735
+4322674655533
+`
+
+// The second example of part 2, where the ultra crucible has to go on past
+// the bottom row before turning.
+const example2 = `111111111111
+999999999991
+999999999991
+999999999991
+999999999991
+`
+
+func TestSolution(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  registry.Options
+		input string
+		part  int
+		want  string
+		err   string
+	}{
+		{name: "example1/part1", input: example1, part: 1, want: "102"},
+		{name: "example1/part2", input: example1, part: 2, want: "94"},
+		{name: "example2/part1", input: example2, part: 1, want: "59"},
+		{name: "example2/part2", input: example2, part: 2, want: "71"},
+		{name: "single block", input: "7\n", part: 1, want: "0"},
+		// Custom rules are only checked against the rules of the part.
+		{name: "zigzag", opts: registry.Options{"max": "1"}, input: "123\n456\n789\n", part: 1, want: "22"},
+		{name: "zigzag", opts: registry.Options{"max": "1"}, input: "123\n456\n789\n", part: 2, err: "invalid run lengths 4~1"},
+		{name: "runs of 2", opts: registry.Options{"min": "2", "max": "2"}, input: "123\n456\n789\n", part: 1, want: "20"},
+		{name: "end", opts: registry.Options{"end": "1,0"}, input: "123\n456\n789\n", part: 1, want: "2"},
+		{name: "start out of the map", opts: registry.Options{"start": "5,5"}, input: "123\n456\n789\n", part: 1, err: "invalid start"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sol{}
+			if err := s.SetOptions(tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			s.WithInput(strings.NewReader(tt.input))
+			got, err := s.solve(tt.part)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, expected %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -24,11 +24,46 @@ func (d Direction) TurnRight() Direction {
 	return (d + 1) % 4
 }
 
+// Turn is a change of direction, relative to the current one.
+type Turn uint8
+
+const (
+	TurnRight Turn = 1
+	TurnBack  Turn = 2
+	TurnLeft  Turn = 3
+)
+
+func (d Direction) Turn(t Turn) Direction {
+	return (d + Direction(t)) % 4
+}
+
 var vects = [...][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
 
 type bestResult struct {
 	steps uint8 // steps in a straight line
 	loss  int   // accumulated loss, less is better
+	trace int32
+}
+
+// trace is a node of the search, linked to the one it was reached from by its
+// index. Traces are kept in a slice without pointers, which is much lighter on
+// the garbage collector than linked nodes.
+type trace struct {
+	coord Coordinate
+	dir   Direction
+	steps uint8
+	loss  int
+	prev  int32
+}
+
+// chain returns the traces leading to traces[end], from the start.
+func chain(traces []trace, end int32) []trace {
+	var ret []trace
+	for i := end; i >= 0; i = traces[i].prev {
+		ret = append(ret, traces[i])
+	}
+	slices.Reverse(ret)
+	return ret
 }
 
 func (b bestResult) noBetterThan(a bestResult) bool {
@@ -41,8 +76,8 @@ type Cell struct {
 	best [4][]bestResult
 }
 
-func (c *Cell) updateBest(dir Direction, steps uint8, loss int) (updated bool) {
-	cur := bestResult{steps: steps, loss: loss}
+func (c *Cell) updateBest(dir Direction, steps uint8, loss int, t int32) (updated bool) {
+	cur := bestResult{steps: steps, loss: loss, trace: t}
 	if c.best[dir] == nil {
 		c.best[dir] = []bestResult{cur}
 		return true
@@ -64,17 +99,19 @@ func (c *Cell) updateBest(dir Direction, steps uint8, loss int) (updated bool) {
 	return true
 }
 
-func (c *Cell) leastLoss() int {
+// leastLoss returns the least loss to reach the cell, and the trace of how it
+// was reached.
+func (c *Cell) leastLoss() (int, int32) {
 	ll := -1
+	var t int32 = -1
 	for i := range c.best {
 		for _, b := range c.best[i] {
-			if ll == -1 {
-				ll = b.loss
+			if ll == -1 || b.loss < ll {
+				ll, t = b.loss, b.trace
 			}
-			ll = min(ll, b.loss)
 		}
 	}
-	return ll
+	return ll, t
 }
 
 type Coordinate struct {
@@ -102,84 +139,143 @@ type queueElem struct {
 	dir             Direction
 	steps           uint8
 	accumulatedLoss int
+	prev            int32
 }
 
-func (m Map) findLeastLoss() int {
-	queue := make([]queueElem, 0, 1024)
-	queue = append(queue,
-		queueElem{Coordinate{1, 0}, Right, 1, 0},
-		queueElem{Coordinate{0, 1}, Down, 1, 0},
-	)
-	for ; len(queue) > 0; queue = queue[1:] {
-		cur := &queue[0]
-		cell := &m[cur.coord.y][cur.coord.x]
-		lDir := cur.dir.TurnLeft()
-		rDir := cur.dir.TurnRight()
-		loss := cur.accumulatedLoss + int(cell.loss)
-
-		var maybeBetter = []bool{
-			cell.updateBest(cur.dir, cur.steps, loss),
-			cell.updateBest(lDir, 0, loss),
-			cell.updateBest(rDir, 0, loss),
+// findLeastLoss searches the path with the least loss from start to end. After
+// a turn, and at the start, the crucible moves r.MinRun blocks at once, so that
+// every state is free to turn or stop, and fewer steps in a straight line is
+// always better.
+//
+// States are queued in buckets by loss, so that they are expanded from the
+// least loss, and most are dropped by updateBest right away.
+func (m Map) findLeastLoss(r *Rules, start, end Coordinate) (int, []trace) {
+	traces := []trace{{coord: start, prev: -1}}
+	var buckets [][]queueElem
+	push := func(e queueElem) {
+		loss := e.accumulatedLoss + int(m[e.coord.y][e.coord.x].loss)
+		for len(buckets) <= loss {
+			buckets = append(buckets, nil)
+		}
+		buckets[loss] = append(buckets[loss], e)
+	}
+	jump := func(pos Coordinate, dir Direction, loss int, prev int32) {
+		for range r.MinRun - 1 {
+			if pos = pos.Move(dir, 1); !m.Contains(pos) {
+				return
+			}
+			loss += int(m[pos.y][pos.x].loss)
 		}
-		newElems := []queueElem{
-			{cur.coord.Move(cur.dir, 1), cur.dir, cur.steps + 1, loss},
-			{cur.coord.Move(lDir, 1), lDir, 1, loss},
-			{cur.coord.Move(rDir, 1), rDir, 1, loss},
+		if pos = pos.Move(dir, 1); m.Contains(pos) {
+			push(queueElem{pos, dir, uint8(r.MinRun), loss, prev})
 		}
-		for i, elem := range newElems {
-			if maybeBetter[i] && elem.steps <= 3 && m.Contains(elem.coord) {
-				queue = append(queue, elem)
+	}
+
+	for dir := range Direction(4) {
+		jump(start, dir, 0, 0)
+	}
+	for loss := 0; loss < len(buckets); loss++ {
+		// Moving to a block without loss adds to the current bucket.
+		for i := 0; i < len(buckets[loss]); i++ {
+			cur := buckets[loss][i]
+			cell := &m[cur.coord.y][cur.coord.x]
+			t := int32(len(traces))
+
+			if !cell.updateBest(cur.dir, cur.steps, loss, t) {
+				continue
+			}
+			traces = append(traces, trace{cur.coord, cur.dir, cur.steps, loss, cur.prev})
+
+			if next := cur.coord.Move(cur.dir, 1); int(cur.steps) < r.MaxRun && m.Contains(next) {
+				push(queueElem{next, cur.dir, cur.steps + 1, loss, t})
+			}
+			for _, turn := range r.Turns {
+				jump(cur.coord, cur.dir.Turn(turn), loss, t)
 			}
 		}
+		buckets[loss] = nil
 	}
 
-	return m[len(m)-1][len(m[0])-1].leastLoss()
+	ll, last := m[end.y][end.x].leastLoss()
+	if last < 0 {
+		return ll, nil
+	}
+	return ll, chain(traces, last)
 }
 
-func (m Map) findLeastLoss2() int {
-	startPos := Coordinate{0, 0}
-	accumulateLoss := func(prev int, pos Coordinate, dir Direction, steps int) int {
-		sum := prev
-		for range steps {
-			if pos = pos.Move(dir, 1); m.Contains(pos) {
-				sum += int(m[pos.y][pos.x].loss)
-			} else {
-				break
+// Step is a cell on the path of the crucible, with the direction it entered
+// the cell, the number of steps made in a straight line so far, and the loss
+// accumulated when leaving the cell.
+type Step struct {
+	Coordinate
+	Dir  Direction
+	Run  int
+	Loss int
+}
+
+type Path []Step
+
+// path expands a chain of traces to every cell visited.
+func (m Map) path(traces []trace) (Path, error) {
+	ret := Path{{Coordinate: traces[0].coord}}
+	for _, t := range traces[1:] {
+		last := ret[len(ret)-1]
+		n := abs(t.coord.x-last.x) + abs(t.coord.y-last.y)
+		for i := 1; i <= n; i++ {
+			pos := last.Move(t.dir, i)
+			if !m.Contains(pos) {
+				return nil, fmt.Errorf("path goes out of the map at %v", pos)
 			}
+			prev := ret[len(ret)-1]
+			ret = append(ret, Step{pos, t.dir, int(t.steps) - n + i, prev.Loss + int(m[pos.y][pos.x].loss)})
 		}
-		return sum
-	}
-
-	queue := make([]queueElem, 0, 1024)
-	queue = append(queue,
-		queueElem{Coordinate{4, 0}, Right, 4, accumulateLoss(0, startPos, Right, 3)},
-		queueElem{Coordinate{0, 4}, Down, 4, accumulateLoss(0, startPos, Down, 3)},
-	)
-	for ; len(queue) > 0; queue = queue[1:] {
-		cur := &queue[0]
-		cell := &m[cur.coord.y][cur.coord.x]
-		lDir := cur.dir.TurnLeft()
-		rDir := cur.dir.TurnRight()
-		loss := cur.accumulatedLoss + int(cell.loss)
-
-		if !cell.updateBest(cur.dir, cur.steps, loss) {
-			continue
+		if cur := ret[len(ret)-1]; cur.Coordinate != t.coord || cur.Loss != t.loss {
+			return nil, fmt.Errorf("path to %v has loss %d, expected %d at %v", cur.Coordinate, cur.Loss, t.loss, t.coord)
 		}
+	}
+	return ret, nil
+}
 
-		newElems := []queueElem{
-			{cur.coord.Move(cur.dir, 1), cur.dir, cur.steps + 1, loss},
-			{cur.coord.Move(lDir, 4), lDir, 4, accumulateLoss(loss, cur.coord, lDir, 3)},
-			{cur.coord.Move(rDir, 4), rDir, 4, accumulateLoss(loss, cur.coord, rDir, 3)},
+// validate checks that the path is made of moves allowed by the rules.
+func (p Path) validate(r *Rules) error {
+	for i := 1; i < len(p); i++ {
+		prev, cur := p[i-1], p[i]
+		if prev.Move(cur.Dir, 1) != cur.Coordinate {
+			return fmt.Errorf("step %d: %v is not next to %v", i, cur.Coordinate, prev.Coordinate)
 		}
-		for _, elem := range newElems {
-			if elem.steps <= 10 && m.Contains(elem.coord) {
-				queue = append(queue, elem)
+		run := 1
+		if i > 1 && cur.Dir == prev.Dir {
+			run = prev.Run + 1
+		} else if i > 1 {
+			if turn := Turn((cur.Dir + 4 - prev.Dir) % 4); !slices.Contains(r.Turns, turn) {
+				return fmt.Errorf("step %d: turned %s at %v", i, turnNames[turn], prev.Coordinate)
+			}
+			if prev.Run < r.MinRun {
+				return fmt.Errorf("step %d: turned after %d steps at %v", i, prev.Run, prev.Coordinate)
 			}
 		}
+		if cur.Run != run {
+			return fmt.Errorf("step %d: run length is %d, expected %d", i, cur.Run, run)
+		}
+		if run > r.MaxRun {
+			return fmt.Errorf("step %d: moved %d steps in a straight line", i, run)
+		}
+	}
+	if last := p[len(p)-1]; last.Run < r.MinRun {
+		return fmt.Errorf("stopped after %d steps in a straight line", last.Run)
 	}
+	return nil
+}
+
+func (p Path) Loss() int {
+	return p[len(p)-1].Loss
+}
 
-	return m[len(m)-1][len(m[0])-1].leastLoss()
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
 }
 
 func readMap(input io.Reader) (Map, error) {
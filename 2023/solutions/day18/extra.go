@@ -2,33 +2,51 @@ package day18
 
 import (
 	"image"
-	"image/draw"
+	"image/color"
+
+	"github.com/kanna5/advent_of_code/common/viz"
 )
 
-func drawMap(instructions []Instruction) image.Image {
-	// Find min and max coordinate
+// drawMap draws the trench, each instruction in its own color.
+func drawMap(instructions []Instruction) *viz.Polylines {
+	ret := &viz.Polylines{}
 	cur := image.Pt(0, 0)
-	minPt, maxPt := cur, cur
 	for _, inst := range instructions {
 		vect := vects[inst.direction]
-		cur.X += vect[0] * int(inst.distance)
-		cur.Y -= vect[1] * int(inst.distance)
-		minPt.X = min(minPt.X, cur.X)
-		minPt.Y = min(minPt.Y, cur.Y)
-		maxPt.X = max(maxPt.X, cur.X)
-		maxPt.Y = max(maxPt.Y, cur.Y)
+		next := image.Pt(cur.X+vect[0]*int(inst.distance), cur.Y+vect[1]*int(inst.distance))
+		path := viz.Path{Points: []image.Point{cur, next}}
+		if inst.color.A != 0 {
+			path.Stroke = inst.color
+		}
+		ret.Paths = append(ret.Paths, path)
+		cur = next
 	}
+	return ret
+}
 
-	img := image.NewRGBA(image.Rect(minPt.X, minPt.Y, maxPt.X+1, maxPt.Y+1))
-	draw.Draw(img, img.Bounds(), image.White, image.Pt(0, 0), draw.Src)
-
-	cur = image.Pt(0, 0)
+// drawLagoon draws the outline of the lagoon, filled.
+func drawLagoon(instructions []Instruction) *viz.Polylines {
+	path := viz.Path{Closed: true, Fill: color.RGBA{0xcf, 0xe8, 0xfc, 0xff}}
+	cur := image.Pt(0, 0)
 	for _, inst := range instructions {
 		vect := vects[inst.direction]
-		for range inst.distance {
-			cur = image.Pt(cur.X+vect[0], cur.Y-vect[1])
-			img.Set(cur.X, cur.Y, inst.color)
-		}
+		cur = image.Pt(cur.X+vect[0]*int(inst.distance), cur.Y+vect[1]*int(inst.distance))
+		path.Points = append(path.Points, cur)
+	}
+	return &viz.Polylines{Paths: []viz.Path{path}}
+}
+
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	parse := parseInstructionText
+	if part == 2 {
+		parse = parseInstructionBinary
+	}
+	instructions, err := s.readInstructions(parse)
+	if err != nil {
+		return nil, err
 	}
-	return img
+	return []viz.Figure{
+		{Name: "trench", Scene: drawMap(instructions)},
+		{Name: "lagoon", Scene: drawLagoon(instructions)},
+	}, nil
 }
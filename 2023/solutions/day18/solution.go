@@ -6,13 +6,11 @@ package day18
 import (
 	"bufio"
 	"fmt"
-	"image/png"
 	"io"
-	"log"
-	"os"
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -48,20 +46,6 @@ func (s *sol) SolvePart1() (string, error) {
 		return "", err
 	}
 
-	if os.Getenv("DRAW") == "1" {
-		img := drawMap(instructions)
-		imgFileName := "day18.png"
-		imgFile, err := os.OpenFile(imgFileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-		if err != nil {
-			return "", err
-		}
-		defer func() { _ = imgFile.Close() }()
-		if err := png.Encode(imgFile, img); err != nil {
-			return "", err
-		}
-		log.Printf("Image saved to %q", imgFileName)
-	}
-
 	state := State{}
 	for _, inst := range instructions {
 		state.Run(inst)
@@ -82,11 +66,13 @@ func (s *sol) SolvePart2() (string, error) {
 	return strconv.FormatInt(state.Volume(), 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[18] = &sol{}
+	registry.Register(solutions.Year, 18, &sol{}, registry.Meta{
+		Title: "Lavaduct Lagoon",
+		Tags:  []registry.Tag{registry.Geometry, registry.Math},
+	})
 }
@@ -0,0 +1,235 @@
+package day19
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The workflows are compiled into a flat list of disjoint hyper-rectangles
+// covering every possible part. Each one remembers the rules leading to it and
+// where it ends: accepted, rejected, or somewhere broken. The walk splitting
+// the parts by the rules is kept as a tree, to find the hyper-rectangle of a
+// part by following the rules it matches.
+
+// Step is a rule matched while walking the workflows.
+type Step struct {
+	workflow string
+	rule     int
+}
+
+type Leaf struct {
+	box   Constraint
+	trail []Step
+	dest  string // TgtAccept or TgtReject, unless the walk is broken
+	cycle bool   // dest is a workflow already on the trail
+}
+
+// node is a workflow visited while walking the workflows, or a leaf.
+type node struct {
+	workflow string
+	next     []*node // Reached by each rule, then by no rule. Nil if no part can.
+	leaf     int     // Index of the leaf if the walk ends here, or -1
+}
+
+type Compiled struct {
+	workflows map[string]*Workflow
+	leaves    []Leaf
+	root      *node
+	matched   map[Step]bool // Rules matched by any part
+	reached   map[string]bool
+}
+
+func compile(wfs map[string]*Workflow) *Compiled {
+	c := &Compiled{
+		workflows: wfs,
+		matched:   map[Step]bool{},
+		reached:   map[string]bool{},
+	}
+	c.root = c.walk("in", *NewConstraint(), nil)
+	return c
+}
+
+func (c *Compiled) walk(name string, box Constraint, trail []Step) *node {
+	leaf := func(cycle bool) *node {
+		c.leaves = append(c.leaves, Leaf{box: box, trail: slices.Clone(trail), dest: name, cycle: cycle})
+		return &node{leaf: len(c.leaves) - 1}
+	}
+	if name == TgtAccept || name == TgtReject {
+		return leaf(false)
+	}
+	wf, ok := c.workflows[name]
+	if !ok {
+		return leaf(false)
+	}
+	if slices.ContainsFunc(trail, func(s Step) bool { return s.workflow == name }) {
+		return leaf(true)
+	}
+	c.reached[name] = true
+
+	n := &node{workflow: name, next: make([]*node, len(wf.rules)+1), leaf: -1}
+	for i := range wf.rules {
+		rule := &wf.rules[i]
+		next := box
+		if next.Apply(rule) {
+			step := Step{name, i}
+			c.matched[step] = true
+			n.next[i] = c.walk(rule.destination, next, append(trail, step))
+		}
+		if rule.operator == OpAny || !box.Apply(rule.Reverse()) {
+			return n
+		}
+	}
+	// No rule matched: the workflow has no default rule.
+	trail = append(trail, Step{name, len(wf.rules)})
+	name = TgtInvalid
+	n.next[len(wf.rules)] = leaf(false)
+	return n
+}
+
+// Find returns the leaf containing a part, or nil if the part is out of range.
+func (c *Compiled) Find(p *Part) *Leaf {
+	if !NewConstraint().Contains(p) {
+		return nil
+	}
+	n := c.root
+	for n.leaf == -1 {
+		rules := c.workflows[n.workflow].rules
+		i := slices.IndexFunc(rules, func(r Rule) bool { return r.Match(p) })
+		if i == -1 {
+			i = len(rules)
+		}
+		n = n.next[i]
+	}
+	return &c.leaves[n.leaf]
+}
+
+func (c *Compiled) Combinations() int64 {
+	var ret int64
+	for i := range c.leaves {
+		if c.leaves[i].dest == TgtAccept {
+			ret += c.leaves[i].box.Combinations()
+		}
+	}
+	return ret
+}
+
+func (l *Leaf) broken() bool {
+	return l.cycle || (l.dest != TgtAccept && l.dest != TgtReject)
+}
+
+func (c *Compiled) describe(l *Leaf) string {
+	switch {
+	case l.cycle:
+		names := []string{}
+		for _, s := range l.trail {
+			names = append(names, s.workflow)
+		}
+		return fmt.Sprintf("cycle %s -> %s", strings.Join(names, " -> "), l.dest)
+	case l.dest == TgtInvalid:
+		return fmt.Sprintf("no rule matches in workflow %q", l.trail[len(l.trail)-1].workflow)
+	case l.dest != TgtAccept && l.dest != TgtReject:
+		if len(l.trail) == 0 {
+			return fmt.Sprintf("no workflow with name %q", l.dest)
+		}
+		last := l.trail[len(l.trail)-1]
+		return fmt.Sprintf("undefined workflow %q targeted by %s", l.dest, c.ruleName(last))
+	}
+	return ""
+}
+
+func (c *Compiled) ruleName(s Step) string {
+	return fmt.Sprintf("%s[%d] %q", s.workflow, s.rule, c.workflows[s.workflow].rules[s.rule].String())
+}
+
+// Err returns an error if some parts reach a cycle, an undefined workflow or
+// the end of a workflow, as their fate is unknown.
+func (c *Compiled) Err() error {
+	for i := range c.leaves {
+		if l := &c.leaves[i]; l.broken() {
+			return fmt.Errorf("%d combinations are not sorted: %s", l.box.Combinations(), c.describe(l))
+		}
+	}
+	return nil
+}
+
+// Check lists the problems found in the workflows: rules no part can match,
+// workflows never reached, undefined targets and cycles.
+func (c *Compiled) Check() []string {
+	ret := []string{}
+	seen := map[string]bool{}
+	for i := range c.leaves {
+		if l := &c.leaves[i]; l.broken() {
+			if msg := c.describe(l); !seen[msg] {
+				seen[msg] = true
+				ret = append(ret, msg)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(c.workflows))
+	for name := range c.workflows {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		wf := c.workflows[name]
+		for i := range wf.rules {
+			dest := wf.rules[i].destination
+			if _, ok := c.workflows[dest]; !ok && dest != TgtAccept && dest != TgtReject {
+				if msg := fmt.Sprintf("undefined workflow %q targeted by %s", dest, c.ruleName(Step{name, i})); !seen[msg] {
+					seen[msg] = true
+					ret = append(ret, msg)
+				}
+			}
+		}
+		if !c.reached[name] {
+			ret = append(ret, fmt.Sprintf("workflow %q is never reached", name))
+			continue
+		}
+		for i := range wf.rules {
+			if !c.matched[Step{name, i}] {
+				ret = append(ret, fmt.Sprintf("rule %s is unreachable", c.ruleName(Step{name, i})))
+			}
+		}
+	}
+	return ret
+}
+
+// Explain tells how a part goes through the workflows, one line per workflow.
+func (c *Compiled) Explain(p *Part) (string, error) {
+	l := c.Find(p)
+	if l == nil {
+		return "", fmt.Errorf("part %v is out of range", p)
+	}
+
+	sb := strings.Builder{}
+	switch {
+	case l.broken():
+		fmt.Fprintf(&sb, "%v is not sorted: %s\n", p, c.describe(l))
+	case l.dest == TgtAccept:
+		fmt.Fprintf(&sb, "%v is accepted\n", p)
+	default:
+		fmt.Fprintf(&sb, "%v is rejected\n", p)
+	}
+	for _, s := range l.trail {
+		wf := c.workflows[s.workflow]
+		evals := []string{}
+		for i := range s.rule {
+			evals = append(evals, fmt.Sprintf("%s (%c=%d) no", wf.rules[i].Condition(), wf.rules[i].propName, p.GetProp(wf.rules[i].propName)))
+		}
+		if s.rule == len(wf.rules) {
+			evals = append(evals, "no rule matches")
+			fmt.Fprintf(&sb, "  %s: %s\n", s.workflow, strings.Join(evals, ", "))
+			break
+		}
+		rule := &wf.rules[s.rule]
+		if rule.operator == OpAny {
+			evals = append(evals, "-> "+rule.destination)
+		} else {
+			evals = append(evals, fmt.Sprintf("%s (%c=%d) yes -> %s", rule.Condition(), rule.propName, p.GetProp(rule.propName), rule.destination))
+		}
+		fmt.Fprintf(&sb, "  %s: %s\n", s.workflow, strings.Join(evals, ", "))
+	}
+	return sb.String(), nil
+}
@@ -1,14 +1,22 @@
 // Solution for https://adventofcode.com/2023/day/19
 package day19
 
+// The workflows are compiled into hyper-rectangles of parts, then part 1
+// follows the rules to the rectangle of each part, and part 2 adds up the
+// accepted ones. Part 1 only fails if a given part is not sorted, part 2 if any
+// possible part is not.
+// Set EXPLAIN to a part, or "all", to see how parts go through the workflows.
+
 import (
 	"bufio"
 	"fmt"
 	"io"
+	"log"
+	"os"
 	"strconv"
 
-	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -53,81 +61,86 @@ func (s *sol) readInput() (map[string]*Workflow, []*Part, error) {
 	return workflows, parts, nil
 }
 
-func (s *sol) SolvePart1() (string, error) {
-	workflows, parts, err := s.readInput()
-	if err != nil {
-		return "", err
+// explain logs the problems found in the workflows and how parts are sorted,
+// if the environment variable EXPLAIN is set. It can be a part such as
+// "{x=787,m=2655,a=1222,s=2876}", or "all" for every part of the input.
+func explain(c *Compiled, parts []*Part) error {
+	what := os.Getenv("EXPLAIN")
+	if len(what) == 0 {
+		return nil
 	}
-
-	sum := 0
-	for _, part := range parts {
-		wf, ok := workflows["in"]
-		if !ok {
-			return "", fmt.Errorf("no workflow with name \"in\"")
+	for _, msg := range c.Check() {
+		log.Printf("check: %s", msg)
+	}
+	if what != "all" {
+		p, err := parsePart(what)
+		if err != nil {
+			return fmt.Errorf("invalid part %q in environment variable EXPLAIN: %v", what, err)
 		}
-		for {
-			next := wf.Eval(part)
-			if next == TgtAccept {
-				sum += part.Sum()
-				break
-			}
-			if next == TgtReject {
-				break
-			}
-			if wf, ok = workflows[next]; !ok {
-				break
-			}
+		parts = []*Part{p}
+	}
+	for _, p := range parts {
+		msg, err := c.Explain(p)
+		if err != nil {
+			return err
 		}
+		log.Print(msg)
 	}
-
-	return strconv.FormatInt(int64(sum), 10), nil
+	return nil
 }
 
-func findCombinations(
-	wfName string, wfs map[string]*Workflow, constraint Constraint, walked lib.Set[string],
-) int64 {
-	if wfName == TgtReject || walked.Has(wfName) || !constraint.valid {
-		return 0
-	}
-	if wfName == TgtAccept {
-		return constraint.Combinations()
+func (s *sol) compile() (*Compiled, []*Part, error) {
+	workflows, parts, err := s.readInput()
+	if err != nil {
+		return nil, nil, err
 	}
-	wf, ok := wfs[wfName]
-	if !ok {
-		return 0
+	c := compile(workflows)
+	if err := explain(c, parts); err != nil {
+		return nil, nil, err
 	}
+	return c, parts, nil
+}
 
-	walked.Add(wfName)
-	defer walked.Del(wfName)
-
-	var sum int64
-	for _, rule := range wf.rules {
-		// If rule matches (recurse)
-		nextConstraint := constraint
-		nextConstraint.Apply(&rule)
-		sum += findCombinations(rule.destination, wfs, nextConstraint, walked)
+func (s *sol) SolvePart1() (string, error) {
+	c, parts, err := s.compile()
+	if err != nil {
+		return "", err
+	}
 
-		// If rule didn't match
-		constraint.Apply(rule.Reverse())
+	sum := 0
+	for _, part := range parts {
+		l := c.Find(part)
+		if l == nil {
+			return "", fmt.Errorf("part %v is out of range", part)
+		}
+		if l.broken() {
+			return "", fmt.Errorf("part %v is not sorted: %s", part, c.describe(l))
+		}
+		if l.dest == TgtAccept {
+			sum += part.Sum()
+		}
 	}
-	return sum
+	return strconv.FormatInt(int64(sum), 10), nil
 }
 
 func (s *sol) SolvePart2() (string, error) {
-	workflows, _, err := s.readInput()
+	c, _, err := s.compile()
 	if err != nil {
 		return "", err
 	}
-
-	sum := findCombinations("in", workflows, *NewConstraint(), lib.Set[string]{})
-	return strconv.FormatInt(sum, 10), nil
+	if err := c.Err(); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(c.Combinations(), 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[19] = &sol{}
+	registry.Register(solutions.Year, 19, &sol{}, registry.Meta{
+		Title: "Aplenty",
+		Tags:  []registry.Tag{registry.Graph},
+	})
 }
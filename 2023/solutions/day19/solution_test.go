@@ -0,0 +1,95 @@
+package day19
+
+import (
+	"strings"
+	"testing"
+)
+
+const example = `px{a<2006:qkq,m>2090:A,rfg}
+pv{a>1716:R,A}
+lnx{m>1548:A,A}
+rfg{s<537:gd,x>2440:R,A}
+qs{s>3448:A,lnx}
+qkq{x<1416:A,crn}
+crn{x>2662:A,R}
+in{s<1351:px,qqz}
+qqz{s>2770:qs,m<1801:hdj,R}
+gd{a>3333:R,R}
+hdj{m>838:A,pv}
+
+{x=787,m=2655,a=1222,s=2876}
+{x=1679,m=44,a=2067,s=496}
+{x=2036,m=264,a=79,s=2244}
+{x=2461,m=1339,a=466,s=291}
+{x=2127,m=1623,a=2188,s=1013}
+`
+
+func TestSolution(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		part1    string
+		part1Err string
+		part2    string
+		part2Err string
+	}{
+		{name: "example", input: example, part1: "19114", part2: "167409079868000"},
+		{
+			// Only parts with x > 2000 loop.
+			name:     "cycle",
+			input:    "in{x>2000:a,A}\na{m>0:b,R}\nb{a>0:a,R}\n\n{x=1,m=1,a=1,s=1}\n{x=5,m=1,a=1,s=3}\n",
+			part1:    "14",
+			part2Err: "cycle in -> a -> b -> a",
+		},
+		{
+			name:     "cycle hit",
+			input:    "in{x>2000:a,A}\na{m>0:b,R}\nb{a>0:a,R}\n\n{x=2001,m=1,a=1,s=1}\n",
+			part1Err: "cycle in -> a -> b -> a",
+			part2Err: "cycle in -> a -> b -> a",
+		},
+		{
+			name:     "undefined",
+			input:    "in{s<100:nope,R}\n\n{x=1,m=1,a=1,s=100}\n",
+			part1:    "0",
+			part2Err: `undefined workflow "nope" targeted by in[0] "s<100:nope"`,
+		},
+		{
+			name:     "undefined hit",
+			input:    "in{s<100:nope,R}\n\n{x=1,m=1,a=1,s=99}\n",
+			part1Err: `undefined workflow "nope"`,
+			part2Err: `undefined workflow "nope"`,
+		},
+		{
+			name:     "no default rule",
+			input:    "in{s<100:A}\n\n{x=1,m=1,a=1,s=99}\n",
+			part1:    "102",
+			part2Err: `no rule matches in workflow "in"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for part, want := range map[int][2]string{1: {tt.part1, tt.part1Err}, 2: {tt.part2, tt.part2Err}} {
+				s := &sol{}
+				s.WithInput(strings.NewReader(tt.input))
+				var got string
+				var err error
+				if part == 1 {
+					got, err = s.SolvePart1()
+				} else {
+					got, err = s.SolvePart2()
+				}
+				if want[1] != "" {
+					if err == nil || !strings.Contains(err.Error(), want[1]) {
+						t.Errorf("part %d: got error %v, expected %q", part, err, want[1])
+					}
+					continue
+				}
+				if err != nil {
+					t.Errorf("part %d: %v", part, err)
+				} else if got != want[0] {
+					t.Errorf("part %d: got %q, expected %q", part, got, want[0])
+				}
+			}
+		})
+	}
+}
@@ -41,6 +41,10 @@ func (p *Part) SetProp(propName byte, val int) {
 	}
 }
 
+func (p *Part) String() string {
+	return fmt.Sprintf("{x=%d,m=%d,a=%d,s=%d}", p[0], p[1], p[2], p[3])
+}
+
 func (p *Part) Sum() int {
 	return p[0] + p[1] + p[2] + p[3]
 }
@@ -73,6 +77,25 @@ func (r *Rule) Match(p *Part) bool {
 	return false
 }
 
+// Condition returns the condition of the rule as in the input, or an empty
+// string if the rule matches any part.
+func (r *Rule) Condition() string {
+	switch r.operator {
+	case OpGt:
+		return fmt.Sprintf("%c>%d", r.propName, r.operand)
+	case OpLt:
+		return fmt.Sprintf("%c<%d", r.propName, r.operand)
+	}
+	return ""
+}
+
+func (r *Rule) String() string {
+	if r.operator == OpAny {
+		return r.destination
+	}
+	return r.Condition() + ":" + r.destination
+}
+
 func (r *Rule) Reverse() *Rule {
 	rev := *r
 	switch r.operator {
@@ -122,6 +145,9 @@ func parseRule(input string) (*Rule, error) {
 		return nil, fmt.Errorf("invalid operator. Must be one of > or <")
 	}
 	prop := parts[0][0]
+	if Xmas(prop) == -1 {
+		return nil, fmt.Errorf("invalid property %q", prop)
+	}
 	num, err := strconv.ParseInt(parts[0][2:], 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("invalid number")
@@ -192,9 +218,9 @@ func (r *Range) Len() int {
 func (r *Range) Apply(operator RuleOp, operand int) {
 	switch operator {
 	case OpGt:
-		r.min = operand + 1
+		r.min = max(r.min, operand+1)
 	case OpLt:
-		r.max = operand - 1
+		r.max = min(r.max, operand-1)
 	}
 }
 
@@ -213,6 +239,18 @@ func (c *Constraint) Apply(r *Rule) bool {
 	return c.valid
 }
 
+func (c *Constraint) Contains(p *Part) bool {
+	if !c.valid {
+		return false
+	}
+	for i, r := range c.ranges {
+		if p[i] < r.min || p[i] > r.max {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *Constraint) Combinations() int64 {
 	if !c.valid {
 		return 0
@@ -0,0 +1,250 @@
+package day20
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/kanna5/advent_of_code/2023/lib"
+)
+
+// A module only reacts to the pulses of its inputs, so the modules sending
+// pulses to it, directly or not, make a cone which can be simulated on its
+// own: the pulses in the cone and their paths are the same as in the whole
+// network. Cones are much smaller than the network, and their states repeat
+// after far fewer presses.
+
+// Presses simulated in a cone while waiting for its state to repeat.
+const maxPresses = 1 << 17
+
+// cone returns a new scene with the named module and those sending pulses to
+// it, directly or not, all in their initial state.
+func (s *Scene) cone(name string) *Scene {
+	members := lib.NewSet(name)
+	for todo := []string{name}; len(todo) > 0; todo = todo[1:] {
+		for _, f := range s.feedersOf(todo[0]) {
+			if !members.Has(f) {
+				members.Add(f)
+				todo = append(todo, f)
+			}
+		}
+	}
+
+	ret := &Scene{modules: map[string]*ModuleConnection{}, moduleNames: []string{}}
+	for _, name := range s.moduleNames {
+		if !members.Has(name) {
+			continue
+		}
+		var m Module
+		switch s.modules[name].Module.(type) {
+		case *FlipFlop:
+			m = NewFlipFlop(name)
+		case *Conjunction:
+			m = NewConjunction(name)
+		default:
+			m = &Broadcaster{}
+		}
+		ret.moduleNames = append(ret.moduleNames, name)
+		ret.modules[name] = &ModuleConnection{Module: m, outputs: s.modules[name].outputs}
+	}
+	for _, name := range ret.moduleNames {
+		for _, oName := range ret.modules[name].outputs {
+			if oMod, ok := ret.modules[oName]; ok {
+				oMod.AddInput(name)
+			}
+		}
+	}
+	return ret
+}
+
+// state encodes the flip-flops and the memories of the conjunctions.
+func (s *Scene) state() string {
+	b := make([]byte, 0, len(s.moduleNames))
+	for _, name := range s.moduleNames {
+		switch m := s.modules[name].Module.(type) {
+		case *FlipFlop:
+			b = append(b, strconv.FormatBool(m.on)[0])
+		case *Conjunction:
+			for _, in := range m.inputs {
+				b = append(b, '0'+byte(m.inputStates[in]))
+			}
+		}
+	}
+	return string(b)
+}
+
+// press records the pulses sent from a module to another during a press.
+type press struct {
+	high   bool    // Whether the last pulse sent before the press is high
+	pulses []pulse // In the order they are handled
+}
+
+// mayBeHigh tells whether the last pulse sent is high at some point of the
+// press.
+func (p *press) mayBeHigh() bool {
+	return p.high || slices.ContainsFunc(p.pulses, func(pl pulse) bool { return pl.p == PulseHigh })
+}
+
+// Watch records the pulses sent from a module to another, until the state of
+// the cone of the sender repeats.
+type Watch struct {
+	prefix, period int64   // Presses before the loop, and in it
+	presses        []press // Every press of the prefix and the loop, from press 1
+}
+
+func watch(sc *Scene, from, to string) (*Watch, error) {
+	c := sc.cone(from)
+	w := &Watch{}
+	high := false
+	seen := map[string]int64{c.state() + strconv.FormatBool(high): 0}
+	for n := int64(1); n <= maxPresses; n++ {
+		p := press{high: high}
+		c.pushBtn(func(pl pulse) {
+			if pl.from == from && pl.to == to {
+				p.pulses = append(p.pulses, pl)
+				high = pl.p == PulseHigh
+			}
+		})
+		w.presses = append(w.presses, p)
+
+		key := c.state() + strconv.FormatBool(high)
+		if first, ok := seen[key]; ok {
+			w.prefix, w.period = first, n-first
+			return w, nil
+		}
+		seen[key] = n
+	}
+	return nil, fmt.Errorf("the modules sending pulses to %q don't repeat their state within %d presses", from, maxPresses)
+}
+
+// At returns the pulses sent during a press, counted from 1.
+func (w *Watch) At(n int64) *press {
+	if n > w.prefix {
+		n = w.prefix + (n-w.prefix-1)%w.period + 1
+	}
+	return &w.presses[n-1]
+}
+
+// firstLow returns the first press where a low pulse is sent, or -1 if none is
+// ever sent.
+func (w *Watch) firstLow() int64 {
+	for i := range w.presses {
+		if slices.ContainsFunc(w.presses[i].pulses, func(pl pulse) bool { return pl.p == PulseLow }) {
+			return int64(i + 1)
+		}
+	}
+	return -1
+}
+
+// loopHits returns the presses of the loop where the last pulse sent may be
+// high.
+func (w *Watch) loopHits() []int64 {
+	ret := []int64{}
+	for n := w.prefix + 1; n <= w.prefix+w.period; n++ {
+		if w.At(n).mayBeHigh() {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// together tells whether a conjunction sends a low pulse during a press, given
+// what its inputs send. It does when an input sends a high pulse while every
+// other input is remembered high.
+func together(presses []*press) bool {
+	type sent struct {
+		*pulse
+		input int
+	}
+	all := []sent{}
+	high := make([]bool, len(presses))
+	for i, p := range presses {
+		for j := range p.pulses {
+			all = append(all, sent{&p.pulses[j], i})
+		}
+		high[i] = p.high
+	}
+	slices.SortFunc(all, func(a, b sent) int { return comparePulses(a.pulse, b.pulse) })
+	for _, s := range all {
+		high[s.input] = s.p == PulseHigh
+		if !slices.Contains(high, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxCombinations limits the systems of congruences to solve, one for each
+// combination of presses in the loops where the inputs may be high.
+const maxCombinations = 1 << 20
+
+// conjunctionLow finds the first press where a conjunction sends a low pulse,
+// or -1 if it never does. The cone of each input is simulated until it loops.
+// The presses until every input is in its loop are checked one by one, then
+// those in the loops where the inputs may be high are combined with the Chinese
+// remainder theorem.
+func conjunctionLow(sc *Scene, name string) (int64, error) {
+	inputs := sc.modules[name].inputs
+	watches := make([]*Watch, len(inputs))
+	var start int64
+	for i, in := range inputs {
+		w, err := watch(sc, in, name)
+		if err != nil {
+			return 0, err
+		}
+		watches[i] = w
+		start = max(start, w.prefix+1)
+	}
+
+	// Some input is still before its loop.
+	presses := make([]*press, len(watches))
+	for n := int64(1); n < start; n++ {
+		for i, w := range watches {
+			presses[i] = w.At(n)
+		}
+		if together(presses) {
+			return n, nil
+		}
+	}
+
+	// Every input is in its loop.
+	combinations := 1
+	hits := make([][]int64, len(watches))
+	periods := make([]int64, len(watches))
+	for i, w := range watches {
+		hits[i], periods[i] = w.loopHits(), w.period
+		combinations *= len(hits[i])
+		if combinations == 0 {
+			return -1, nil
+		}
+		if combinations > maxCombinations {
+			return 0, fmt.Errorf("too many combinations of presses where the inputs of %q are high", name)
+		}
+	}
+	best := int64(-1)
+	residues := make([]int64, len(watches))
+	for n := range combinations {
+		c := n
+		for i, w := range watches {
+			h := hits[i][c%len(hits[i])]
+			presses[i] = w.At(h)
+			residues[i] = h % w.period
+			c /= len(hits[i])
+		}
+		x, lcm, err := lib.Crt(residues, periods)
+		if errors.Is(err, lib.ErrNoSolution) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if x < start {
+			x += (start - x + lcm - 1) / lcm * lcm
+		}
+		if (best == -1 || x < best) && together(presses) {
+			best = x
+		}
+	}
+	return best, nil
+}
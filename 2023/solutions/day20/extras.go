@@ -2,44 +2,197 @@ package day20
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/kanna5/advent_of_code/2023/lib"
+	"github.com/kanna5/advent_of_code/common/registry"
+	"github.com/kanna5/advent_of_code/common/viz"
 )
 
-func drawDiagram(sc *Scene) string {
-	builder := strings.Builder{}
-	fmt.Fprintln(&builder, "digraph \"Day 20\" {")
+// vizOptions tell what to draw.
+type vizOptions struct {
+	Presses int // Button presses simulated before drawing the network
+	Steps   int // First presses drawn after each pulse
+}
+
+var vizOptionKeys = []string{"presses", "steps"}
+
+var defaultVizOptions = [...]vizOptions{
+	1: {Presses: 1000, Steps: 1},
+	2: {Presses: 1000},
+}
+
+// With returns the options changed by:
+//
+//	presses=1000  button presses before drawing the network
+//	steps=1       presses drawn pulse by pulse, 0 for none
+func (v vizOptions) With(opts registry.Options) (vizOptions, error) {
+	if err := opts.Check(vizOptionKeys...); err != nil {
+		return v, err
+	}
+	for key, n := range map[string]*int{"presses": &v.Presses, "steps": &v.Steps} {
+		if s, ok := opts[key]; ok {
+			var err error
+			if *n, err = strconv.Atoi(s); err != nil || *n < 0 {
+				return v, fmt.Errorf("invalid number of %s %q", key, s)
+			}
+		}
+	}
+	if v.Steps > v.Presses {
+		return v, fmt.Errorf("can't draw the pulses of %d presses out of %d", v.Steps, v.Presses)
+	}
+	return v, nil
+}
+
+var pulseNames = [...]string{PulseLow: "low", PulseHigh: "high"}
+
+// recorder counts the pulses sent along each connection.
+type recorder struct {
+	presses int
+	counts  map[[2]string]*[2]int
+}
+
+func (r *recorder) record(pl pulse) {
+	k := [2]string{pl.from, pl.to}
+	if r.counts[k] == nil {
+		r.counts[k] = &[2]int{}
+	}
+	r.counts[k][pl.p]++
+}
+
+func (r *recorder) total() (low, high int) {
+	for _, c := range r.counts {
+		low += c[PulseLow]
+		high += c[PulseHigh]
+	}
+	return low, high
+}
+
+// findCounters looks for binary counters behind the outputs of the
+// broadcaster, and returns their modules by the number they count to.
+func findCounters(sc *Scene) map[int64][]string {
+	ret := map[int64][]string{}
+	broadcaster, ok := sc.modules["broadcaster"]
+	if !ok {
+		return ret
+	}
+	for _, o := range broadcaster.outputs {
+		if num, members, err := decodeBinaryCounter(sc, o); err == nil {
+			ret[num] = members
+		}
+	}
+	return ret
+}
+
+// drawDiagram draws the network in its current state: flip-flops that are on
+// are filled, conjunctions list the last pulse remembered from each input, and
+// connections are labeled with the number of low and high pulses sent. If cur
+// is not nil, the connection carrying it is highlighted.
+func drawDiagram(sc *Scene, rec *recorder, caption string, cur *pulse) *viz.Graph {
+	g := &viz.Graph{Name: "Day 20", Attrs: viz.Attrs{"label": caption, "labelloc": "t"}}
+	defined := lib.NewSet("button")
+	g.AddNode("button", viz.Attrs{"shape": "invtriangle"})
 
-	defined := lib.Set[string]{}
+	addEdge := func(from, to string) {
+		attrs := viz.Attrs{}
+		if c := rec.counts[[2]string{from, to}]; c != nil {
+			attrs["label"] = fmt.Sprintf("%dL %dH", c[PulseLow], c[PulseHigh])
+		}
+		if cur != nil && cur.from == from && cur.to == to {
+			attrs["penwidth"] = "3"
+			attrs["color"] = map[Pulse]string{PulseLow: "blue", PulseHigh: "red"}[cur.p]
+		}
+		g.AddEdge(from, to, attrs)
+	}
+	addEdge("button", "broadcaster")
 
 	for _, name := range sc.moduleNames {
 		mod := sc.modules[name]
 		if !defined.Has(name) {
-			if IsConjunction(mod.Module) {
-				fmt.Fprintf(&builder, "  %s [shape=Msquare style=filled fillcolor=\"/pastel16/3\"];\n", name)
-			} else if IsFlipFlop(mod.Module) {
-				fmt.Fprintf(&builder, "  %s [shape=rect];\n", name)
-			} else if IsBroadcaster(mod.Module) {
-				fmt.Fprintf(&builder, "  %s [shape=doublecircle label=\"START\" style=filled fillcolor=\"/pastel16/1\"];\n", name)
+			switch m := mod.Module.(type) {
+			case *Conjunction:
+				label := []string{name}
+				for _, in := range m.inputs {
+					label = append(label, fmt.Sprintf("%s: %s", in, pulseNames[m.inputStates[in]]))
+				}
+				g.AddNode(name, viz.Attrs{"shape": "Msquare", "style": "filled", "fillcolor": "/pastel16/3", "label": strings.Join(label, "\n")})
+			case *FlipFlop:
+				attrs := viz.Attrs{"shape": "rect", "label": name + "\noff"}
+				if m.on {
+					attrs["label"] = name + "\non"
+					attrs["style"] = "filled"
+					attrs["fillcolor"] = "/pastel16/5"
+				}
+				g.AddNode(name, attrs)
+			case *Broadcaster:
+				g.AddNode(name, viz.Attrs{"shape": "doublecircle", "label": "START", "style": "filled", "fillcolor": "/pastel16/1"})
 			}
 			defined.Add(name)
 		}
 		for _, oName := range mod.outputs {
 			_, ok := sc.modules[oName]
 			if !ok && !defined.Has(oName) {
-				fmt.Fprintf(&builder, "  %s [shape=star style=filled fillcolor=\"/pastel16/6\"];\n", oName)
+				g.AddNode(oName, viz.Attrs{"shape": "star", "style": "filled", "fillcolor": "/pastel16/6"})
 				defined.Add(oName)
 			}
-			fmt.Fprintf(&builder, "  %s -> %s;\n", name, oName)
+			addEdge(name, oName)
 		}
 	}
+	return g
+}
 
-	fmt.Fprintln(&builder, "}")
-	return builder.String()
+// highlightCounters groups the modules of each binary counter.
+func highlightCounters(g *viz.Graph, counters map[int64][]string) {
+	for _, num := range slices.Sorted(maps.Keys(counters)) {
+		members := counters[num]
+		g.AddCluster(fmt.Sprint(num), viz.Attrs{
+			"label":     fmt.Sprintf("counter to %d", num),
+			"style":     "filled",
+			"fillcolor": "/pastel16/2",
+		}, members...)
+	}
 }
 
-var diagramMsg = "" +
-	"Diagram saved to \"./day20.dot\". You can:\n" +
-	"- Use the `dot` command from GraphViz to render the diagram to SVG and view it in a browser.\n" +
-	"- Or view it directly in an online viewer (e.g., https://dreampuf.github.io/GraphvizOnline/)\n"
+// Visualize draws the module network after pushing the button a thousand
+// times, with the binary counters grouped. In part 1, it also draws a snapshot
+// after each pulse of the first press. Both are changed with the options, see
+// vizOptions.With.
+//
+// Use the `dot` command from GraphViz to render the diagrams, or an online
+// viewer (e.g., https://dreampuf.github.io/GraphvizOnline/)
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	vo, err := defaultVizOptions[part].With(s.opts)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := readScene(s.input)
+	if err != nil {
+		return nil, err
+	}
+	rec := &recorder{counts: map[[2]string]*[2]int{}}
+	counters := findCounters(sc)
+
+	var snapshots []viz.Figure
+	for ; rec.presses < vo.Steps; rec.presses++ {
+		n := 0
+		sc.pushBtn(func(pl pulse) {
+			rec.record(pl)
+			n++
+			caption := fmt.Sprintf("press %d, pulse %d: %s -%s-> %s", rec.presses+1, n, pl.from, pulseNames[pl.p], pl.to)
+			g := drawDiagram(sc, rec, caption, &pl)
+			highlightCounters(g, counters)
+			snapshots = append(snapshots, viz.Figure{Name: fmt.Sprintf("press%d-pulse%04d", rec.presses+1, n), Scene: g})
+		})
+	}
+	for ; rec.presses < vo.Presses; rec.presses++ {
+		sc.pushBtn(rec.record)
+	}
+
+	low, high := rec.total()
+	g := drawDiagram(sc, rec, fmt.Sprintf("after %d presses: %d low and %d high pulses", rec.presses, low, high), nil)
+	highlightCounters(g, counters)
+	return append([]viz.Figure{{Name: "network", Scene: g}}, snapshots...), nil
+}
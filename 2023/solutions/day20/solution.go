@@ -1,24 +1,29 @@
 // Solution for https://adventofcode.com/2023/day/20
 package day20
 
-// Note: The key to solving part 2 is to analyze the input structure.
+// Note: The key to solving part 2 is to analyze the input structure. The
+// presses until rx receives a low pulse are found by simulating separately the
+// parts of the network feeding it, until each one loops, or by decoding the
+// binary counters behind the broadcaster if that fails and the network is
+// made of such counters.
 //
 // This solution includes code to draw a GraphViz diagram for inspection
-// (activated with env DRAW=1)
+// (activated with --viz=dot)
 
 import (
 	"fmt"
 	"io"
-	"log"
-	"os"
+	"slices"
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
 	input io.Reader
+	opts  registry.Options
 }
 
 func (s *sol) SolvePart1() (string, error) {
@@ -37,19 +42,25 @@ func (s *sol) SolvePart1() (string, error) {
 	return strconv.FormatInt(int64(high)*int64(low), 10), nil
 }
 
-func decodeBinaryCounter(sc *Scene, entry string) (int64, error) {
+// decodeBinaryCounter decodes the number counted to by the chain of flip-flops
+// starting at entry, and returns the modules making the counter.
+func decodeBinaryCounter(sc *Scene, entry string) (int64, []string, error) {
 	var num int64
+	members := []string{}
 
 	cur := entry
 	for bit := 0; ; bit++ {
+		if bit >= 63 {
+			return 0, nil, fmt.Errorf("too many bits from %q", entry)
+		}
 		curMod, ok := sc.modules[cur]
 		if !ok || !IsFlipFlop(curMod.Module) {
-			return 0, fmt.Errorf("invalid module %q", cur)
+			return 0, nil, fmt.Errorf("invalid module %q", cur)
 		}
 		// Loosely validate the structure...
 		if len(curMod.inputs) > 2 || len(curMod.inputs) == 0 ||
 			len(curMod.outputs) > 2 || len(curMod.outputs) == 0 {
-			return 0, fmt.Errorf("invalid structure at module %#v", curMod)
+			return 0, nil, fmt.Errorf("invalid structure at module %#v", curMod)
 		}
 		// If a module has output to a conjunction, it must be 1; otherwise, 0.
 		// If a module has no output to a flip-flop, it is the last one.
@@ -58,74 +69,170 @@ func decodeBinaryCounter(sc *Scene, entry string) (int64, error) {
 		for _, oName := range curMod.outputs {
 			oMod, ok := sc.modules[oName]
 			if !ok {
-				return 0, fmt.Errorf("invalid structure at module %q: unknown output %q", cur, oName)
+				return 0, nil, fmt.Errorf("invalid structure at module %q: unknown output %q", cur, oName)
 			}
 			switch {
 			case IsConjunction(oMod.Module):
 				if conjunction != "" {
-					return 0, fmt.Errorf("invalid structure at module %q: more than one conjunction", cur)
+					return 0, nil, fmt.Errorf("invalid structure at module %q: more than one conjunction", cur)
 				}
 				conjunction = oName
 			case IsFlipFlop(oMod.Module):
 				if next != "" {
-					return 0, fmt.Errorf("invalid structure at module %q: more than one flip-flop", cur)
+					return 0, nil, fmt.Errorf("invalid structure at module %q: more than one flip-flop", cur)
 				}
 				next = oName
 			default:
-				return 0, fmt.Errorf("invalid structure at module %q: output %q has invalid type", cur, oName)
+				return 0, nil, fmt.Errorf("invalid structure at module %q: output %q has invalid type", cur, oName)
 			}
 		}
+		members = append(members, cur)
 		if conjunction != "" {
 			num += 1 << bit
+			if !slices.Contains(members, conjunction) {
+				members = append(members, conjunction)
+			}
 		}
 		if next == "" {
 			break
 		}
 		cur = next
 	}
-	return num, nil
+	return num, members, nil
 }
 
-func (s *sol) SolvePart2() (string, error) {
-	sc, err := readScene(s.input)
-	if err != nil {
-		return "", err
+// pressesUntilRx finds the number of presses until rx receives a low pulse.
+// Each module sending pulses to rx is looked at on its own, see cones.go: a
+// conjunction sends a low pulse when its inputs are high together, which is
+// found from the cones of its inputs, and other modules from their own cone.
+func pressesUntilRx(sc *Scene) (int64, error) {
+	feeders := sc.feedersOf("rx")
+	if len(feeders) == 0 {
+		return 0, fmt.Errorf("no module sends pulses to rx")
 	}
+	best := int64(-1)
+	for _, f := range feeders {
+		var n int64
+		if IsConjunction(sc.modules[f].Module) {
+			var err error
+			if n, err = conjunctionLow(sc, f); err != nil {
+				return 0, err
+			}
+		} else {
+			w, err := watch(sc, f, "rx")
+			if err != nil {
+				return 0, err
+			}
+			n = w.firstLow()
+		}
+		if n != -1 && (best == -1 || n < best) {
+			best = n
+		}
+	}
+	if best == -1 {
+		return 0, fmt.Errorf("rx never receives a low pulse")
+	}
+	return best, nil
+}
 
-	if os.Getenv("DRAW") == "1" {
-		diag := drawDiagram(sc)
-		diagFile, err := os.OpenFile("day20.dot", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-		if err != nil {
-			return "", fmt.Errorf("failed to write diagram: %v", err)
+// checkCounters checks that rx is fed by a conjunction, whose inputs are
+// inverters each fed by the conjunction of one of the binary counters.
+func checkCounters(sc *Scene, counters [][]string) error {
+	feeders := sc.feedersOf("rx")
+	if len(feeders) != 1 || !IsConjunction(sc.modules[feeders[0]].Module) {
+		return fmt.Errorf("rx is not fed by a single conjunction")
+	}
+	hub := feeders[0]
+
+	inverters := []string{}
+	for _, members := range counters {
+		conjunctions := slices.DeleteFunc(slices.Clone(members), func(m string) bool {
+			return !IsConjunction(sc.modules[m].Module)
+		})
+		if len(conjunctions) != 1 {
+			return fmt.Errorf("counter at %q has %d conjunctions", members[0], len(conjunctions))
+		}
+		others := slices.DeleteFunc(slices.Clone(sc.modules[conjunctions[0]].outputs), func(o string) bool {
+			return slices.Contains(members, o)
+		})
+		if len(others) != 1 {
+			return fmt.Errorf("counter at %q has %d outputs", members[0], len(others))
 		}
-		defer func() { _ = diagFile.Close() }()
-		if _, err = diagFile.WriteString(diag); err != nil {
-			return "", fmt.Errorf("failed to write diagram: %v", err)
+		inv, ok := sc.modules[others[0]]
+		if !ok || !IsConjunction(inv.Module) || len(inv.inputs) != 1 || !slices.Equal(inv.outputs, feeders) {
+			return fmt.Errorf("counter at %q doesn't feed %q through an inverter", members[0], hub)
 		}
-		log.Println(diagramMsg)
+		inverters = append(inverters, others[0])
 	}
+	inputs := slices.Clone(sc.modules[hub].inputs)
+	slices.Sort(inputs)
+	slices.Sort(inverters)
+	if !slices.Equal(inputs, inverters) {
+		return fmt.Errorf("the inputs of %q are not the counters", hub)
+	}
+	return nil
+}
 
+// decodeCounters finds the number of presses until rx receives a low pulse,
+// if every output of the broadcaster is a binary counter, see checkCounters.
+func decodeCounters(sc *Scene) (int64, error) {
 	numbers := make([]int64, 0, 4)
+	counters := [][]string{}
 	broadcaster, ok := sc.modules["broadcaster"]
 	if !ok {
-		return "", fmt.Errorf("no broadcaster node")
+		return 0, fmt.Errorf("no broadcaster node")
 	}
 	for _, o := range broadcaster.outputs {
-		num, err := decodeBinaryCounter(sc, o)
+		num, members, err := decodeBinaryCounter(sc, o)
 		if err != nil {
-			return "", fmt.Errorf("failed to decode binary counter at entry %q: %v", o, err)
+			return 0, fmt.Errorf("failed to decode binary counter at entry %q: %v", o, err)
 		}
 		numbers = append(numbers, num)
+		counters = append(counters, members)
+	}
+	if err := checkCounters(sc, counters); err != nil {
+		return 0, err
+	}
+	return lib.LcmSeq(numbers), nil
+}
+
+func (s *sol) SolvePart2() (string, error) {
+	sc, err := readScene(s.input)
+	if err != nil {
+		return "", err
 	}
 
-	return strconv.FormatInt(lib.LcmSeq(numbers), 10), nil
+	n, err := pressesUntilRx(sc)
+	if err != nil {
+		var err2 error
+		if n, err2 = decodeCounters(sc); err2 != nil {
+			return "", fmt.Errorf("%v, and %v", err, err2)
+		}
+	}
+	return strconv.FormatInt(n, 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
+}
+
+// SetOptions changes what is drawn with --viz, see vizOptions.With. They don't
+// change the answers. The values are checked when drawing, against the default
+// options of the part.
+func (s *sol) SetOptions(opts registry.Options) error {
+	if err := opts.Check(vizOptionKeys...); err != nil {
+		return err
+	}
+	s.opts = opts
+	return nil
 }
 
 func init() {
-	solutions.Days[20] = &sol{}
+	registry.Register(solutions.Year, 20, &sol{}, registry.Meta{
+		Title: "Pulse Propagation",
+		Tags:  []registry.Tag{registry.Simulation, registry.Graph},
+		Assumptions: []string{
+			"The modules sending pulses to rx, or to the conjunctions feeding it, loop within 2^17 presses, or every output of the broadcaster is a binary counter.",
+		},
+	})
 }
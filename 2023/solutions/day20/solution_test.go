@@ -0,0 +1,119 @@
+package day20
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustReadScene(t *testing.T, s string) *Scene {
+	t.Helper()
+	sc, err := readScene(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sc
+}
+
+func TestSolvePart1(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"example1", "broadcaster -> a, b, c\n%a -> b\n%b -> c\n%c -> inv\n&inv -> a\n", "32000000"},
+		{"example2", "broadcaster -> a\n%a -> inv, con\n&inv -> b\n%b -> con\n&con -> output\n", "11687500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sol{}
+			s.WithInput(strings.NewReader(tt.input))
+			got, err := s.SolvePart1()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// bruteForce presses the button until rx receives a low pulse.
+func bruteForce(t *testing.T, input string) int64 {
+	t.Helper()
+	sc := mustReadScene(t, input)
+	for n := int64(1); n <= 1<<16; n++ {
+		sc.PushBtn()
+		if sc.IsActivated() {
+			return n
+		}
+	}
+	t.Fatal("rx receives no low pulse within 2^16 presses")
+	return 0
+}
+
+func TestPressesUntilRx(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"flip-flops", "broadcaster -> a\n%a -> b\n%b -> rx\n"},
+		{"inverter", "broadcaster -> a\n%a -> b\n%b -> c\n&c -> rx\n"},
+		{
+			// Inputs with different periods, high together only once in a
+			// while.
+			"conjunction",
+			"broadcaster -> a, c\n%a -> b\n%b -> hub\n%c -> d\n%d -> e\n%e -> hub\n&hub -> rx\n",
+		},
+		{
+			// One input is high during a press only, before the other
+			// turns high.
+			"within a press",
+			"broadcaster -> a, b\n%a -> hub, c\n%c -> hub\n%b -> d\n%d -> hub\n&hub -> rx\n",
+		},
+		{
+			"counters",
+			"broadcaster -> a, d\n%a -> b, c1\n%b -> c1\n&c1 -> a, i1\n&i1 -> hub\n" +
+				"%d -> e, c2\n%e -> f, c2\n%f -> c2\n&c2 -> d, i2\n&i2 -> hub\n&hub -> rx\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := bruteForce(t, tt.input)
+			got, err := pressesUntilRx(mustReadScene(t, tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %d, expected %d", got, want)
+			}
+		})
+	}
+}
+
+func TestSolvePart2Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"no rx", "broadcaster -> a\n%a -> b\n%b -> output\n", "no module sends pulses to rx"},
+		{"always high", "broadcaster -> a\n&a -> rx\n", "rx never receives a low pulse"},
+		{
+			// A conjunction feeding itself through a flip-flop.
+			"feedback",
+			"broadcaster -> a\n%a -> x, b\n%b -> x\n&x -> y, rx\n%y -> x\n",
+			"rx never receives a low pulse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sol{}
+			s.WithInput(strings.NewReader(tt.input))
+			_, err := s.SolvePart2()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, expected %q", err, tt.want)
+			}
+		})
+	}
+}
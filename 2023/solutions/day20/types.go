@@ -2,6 +2,7 @@ package day20
 
 import (
 	"bufio"
+	"cmp"
 	"fmt"
 	"io"
 	"slices"
@@ -41,37 +42,67 @@ type Scene struct {
 }
 
 func (s *Scene) PushBtn() (int, int) {
+	return s.pushBtn(nil)
+}
+
+// pulse is sent from a module to another while pushing the button. Pulses are
+// handled in waves: the pulses sent while handling a wave make the next one, in
+// the order of the pulses handled, then of the outputs. So the indices of the
+// outputs taken from the button order the pulses of a press.
+type pulse struct {
+	from string
+	p    Pulse
+	to   string
+	path string // One byte per wave
+}
+
+// comparePulses orders the pulses of a press as they are handled.
+func comparePulses(a, b *pulse) int {
+	return cmp.Or(cmp.Compare(len(a.path), len(b.path)), strings.Compare(a.path, b.path))
+}
+
+// pushBtn pushes the button, calling onPulse after each pulse is handled by its
+// target.
+func (s *Scene) pushBtn(onPulse func(pl pulse)) (int, int) {
 	pulses := [...]int{1, 0} // low, high
 
-	type queueElem struct {
-		from   string
-		signal Pulse
-		target string
-	}
-	queue := []queueElem{{"button", PulseLow, "broadcaster"}}
+	queue := []pulse{{"button", PulseLow, "broadcaster", ""}}
 	for ; len(queue) > 0; queue = queue[1:] {
 		cur := queue[0]
-		if cur.target == "rx" && cur.signal == PulseLow {
+		if cur.to == "rx" && cur.p == PulseLow {
 			s.rx = true
-			continue
 		}
-		tgtMod, ok := s.modules[cur.target]
-		if !ok {
-			continue
+		var output *Pulse
+		tgtMod, ok := s.modules[cur.to]
+		if ok {
+			output = tgtMod.Signal(cur.from, cur.p)
+		}
+		if onPulse != nil {
+			onPulse(cur)
 		}
-		output := tgtMod.Signal(cur.from, cur.signal)
 		if output == nil {
 			continue
 		}
 
 		pulses[*output] += len(tgtMod.outputs)
-		for _, oName := range tgtMod.outputs {
-			queue = append(queue, queueElem{cur.target, *output, oName})
+		for i, oName := range tgtMod.outputs {
+			queue = append(queue, pulse{cur.to, *output, oName, cur.path + string(byte(i))})
 		}
 	}
 	return pulses[0], pulses[1]
 }
 
+// feedersOf returns the modules sending pulses to the named one.
+func (s *Scene) feedersOf(name string) []string {
+	ret := []string{}
+	for _, n := range s.moduleNames {
+		if slices.Contains(s.modules[n].outputs, name) {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
 func (s *Scene) IsActivated() bool {
 	return s.rx
 }
@@ -0,0 +1,109 @@
+package day21
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/kanna5/advent_of_code/2023/lib"
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+// Number of map widths walked past the edge of the center tile in the largest
+// comparison of part 2.
+const vizTiles = 4
+
+var (
+	colorReachable = viz.Green
+	colorOtherSide = viz.Mix(viz.Green, viz.White, 0.6) // Reachable with one step more or less
+	colorRock      = viz.Mix(viz.Gray, viz.Black, 0.3)
+)
+
+// drawGarden draws the plots covered in the given steps, on the map tiled
+// radius times around the center tile. The plots reached at the last step are
+// green, and the ones of the other parity light green. If regions is set, the
+// regions used by the formula of part 2 are tinted: the inner diamond in blue
+// and the reverse diamond in purple.
+func drawGarden(map_ *Map, covered lib.Set[Coord], steps, radius int, regions bool) *viz.Grid {
+	var diamond1, diamond3 lib.Set[Coord]
+	if regions {
+		diamond1 = walk(map_, map_.w/2)
+		diamond3 = walk(map_, map_.w/2+map_.w)
+	}
+
+	g := viz.NewGrid(map_.w*(2*radius+1), map_.h*(2*radius+1))
+	g.Scale = max(1, 400/g.W)
+	for gy := range g.H {
+		for gx := range g.W {
+			c := Coord{gx - radius*map_.w, gy - radius*map_.h}
+			cell := g.At(gx, gy)
+			switch {
+			case map_.At(c) == Rock:
+				cell.Ch, cell.BG = '#', colorRock
+			case covered.Has(c) && (lib.Abs(c.x-map_.start.x)+lib.Abs(c.y-map_.start.y))%2 == steps%2:
+				cell.Ch, cell.BG = 'O', colorReachable
+			case covered.Has(c):
+				cell.Ch, cell.BG = '.', colorOtherSide
+			default:
+				cell.Ch = '.'
+			}
+			switch {
+			case diamond1.Has(c):
+				cell.BG = viz.Mix(cellBG(cell), viz.Blue, 0.4)
+			case diamond3.Has(c) && map_.inReverseDiamond(c):
+				cell.BG = viz.Mix(cellBG(cell), viz.Purple, 0.4)
+			}
+		}
+	}
+	return g
+}
+
+func cellBG(c *viz.Cell) color.Color {
+	if c.BG == nil {
+		return viz.White
+	}
+	return c.BG
+}
+
+// Visualize draws the plots reachable in part 1. In part 2, it compares the
+// formula with brute force for small numbers of steps of the form the formula
+// expects, in an animation where mismatches are highlighted in red, and draws
+// the regions used by the formula.
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	map_, err := readMap(s.input)
+	if err != nil {
+		return nil, err
+	}
+
+	if part == 1 {
+		steps, err := getSteps(64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get target steps: %v", err)
+		}
+		plots := reachable(map_, steps)
+		g := drawGarden(map_, plots, steps, 0, false)
+		g.Scale = 0
+		g.Caption = fmt.Sprintf("%d plots in %d steps", len(plots), steps)
+		return []viz.Figure{{Name: "reachable", Scene: g}}, nil
+	}
+
+	distToEdge := map_.w / 2
+	brute := Part2Brute(map_, distToEdge+vizTiles*map_.w)
+	anim := &viz.Animation{Delay: 150}
+	for tiles := range vizTiles + 1 {
+		steps := distToEdge + tiles*map_.w
+		formula := countPlots(map_, steps)
+		frame := drawGarden(map_, walk(map_, steps), steps, vizTiles, false)
+		frame.Caption = fmt.Sprintf("%d steps: formula %d, brute force %d", steps, formula, brute[steps])
+		if formula != int64(brute[steps]) {
+			frame.Highlight = viz.Red
+		}
+		anim.Frames = append(anim.Frames, frame)
+	}
+
+	regions := drawGarden(map_, walk(map_, distToEdge+map_.w), distToEdge+map_.w, 1, true)
+	regions.Caption = "inner diamond and reverse diamond"
+	return []viz.Figure{
+		{Name: "comparison", Scene: anim},
+		{Name: "regions", Scene: regions},
+	}, nil
+}
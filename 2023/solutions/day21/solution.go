@@ -1,7 +1,13 @@
 // Solution for https://adventofcode.com/2023/day/21
 package day21
 
-// The structure of the actual input (again) is key to solving part 2.
+// On the infinite map, the number of plots reachable in r + k*period steps
+// eventually grows quadratically with k, as the covered area is a diamond
+// repeating the same tiles. Part 2 samples these counts with a BFS, fits a
+// quadratic with finite differences once they are stable, and extrapolates.
+//
+// countPlots is the earlier closed formula, which relies on the structure of the
+// actual input:
 // - The starting point is at the center of the map, and the center row and
 //   column are empty.
 // - The map is a square with an odd side length.
@@ -17,6 +23,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -42,6 +49,13 @@ func (s *sol) SolvePart1() (string, error) {
 		return "", fmt.Errorf("failed to get target steps: %v", err)
 	}
 
+	plots := reachable(map_, steps)
+	return strconv.FormatInt(int64(len(plots)), 10), nil
+}
+
+// reachable returns the plots reachable in exactly the given number of steps,
+// without leaving the map.
+func reachable(map_ *Map, steps int) lib.Set[Coord] {
 	plots := lib.NewSet(map_.start)
 	for range steps {
 		nextPlots := make(lib.Set[Coord], len(plots)*11/10)
@@ -55,7 +69,7 @@ func (s *sol) SolvePart1() (string, error) {
 		}
 		plots = nextPlots
 	}
-	return strconv.FormatInt(int64(len(plots)), 10), nil
+	return plots
 }
 
 func Part2Brute(map_ *Map, steps int) []int {
@@ -102,16 +116,16 @@ func walk(map_ *Map, steps int) lib.Set[Coord] {
 	return covered
 }
 
-func (s *sol) SolvePart2() (string, error) {
-	map_, err := readMap(s.input)
-	if err != nil {
-		return "", err
-	}
-	steps, err := getSteps(26501365)
-	if err != nil {
-		return "", fmt.Errorf("failed to get target steps: %v", err)
-	}
+// inReverseDiamond tells whether c is in one of the tiles diagonal to the
+// center tile.
+func (m *Map) inReverseDiamond(c Coord) bool {
+	return (c.x < 0 && c.y < 0) || (c.x >= m.w && c.y < 0) ||
+		(c.x >= m.w && c.y >= m.h) || (c.x < 0 && c.y >= m.h)
+}
 
+// countPlots counts the plots reachable in exactly the given number of steps
+// on the infinite map, from the regions of the center tile and its neighbors.
+func countPlots(map_ *Map, steps int) int64 {
 	var dist = func(x, y int) int { return lib.Abs(x-map_.start.x) + lib.Abs(y-map_.start.y) }
 
 	distToEdge := map_.w / 2
@@ -142,8 +156,7 @@ func (s *sol) SolvePart2() (string, error) {
 
 	var nReverseDiamond int64
 	for c := range diamond3 {
-		if (c.x < 0 && c.y < 0) || (c.x >= map_.w && c.y < 0) ||
-			(c.x >= map_.w && c.y >= map_.h) || (c.x < 0 && c.y >= map_.h) {
+		if map_.inReverseDiamond(c) {
 			if dist(c.x, c.y)%2 == 0 {
 				nReverseDiamond++
 			}
@@ -154,14 +167,106 @@ func (s *sol) SolvePart2() (string, error) {
 	n := (tiles+1)*(tiles+1)*nOdd + tiles*tiles*nEven
 	n -= nDiamondUncovered * (tiles + 1)
 	n += nReverseDiamond * tiles
+	return n
+}
+
+func (s *sol) SolvePart2() (string, error) {
+	map_, err := readMap(s.input)
+	if err != nil {
+		return "", err
+	}
+	steps, err := getSteps(26501365)
+	if err != nil {
+		return "", fmt.Errorf("failed to get target steps: %v", err)
+	}
+	n, err := extrapolate(map_, steps)
+	if err != nil {
+		return "", err
+	}
 	return strconv.FormatInt(n, 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+// The counts are sampled over minSamplePeriods periods at first, and over
+// twice as many each time the quadratic is not found, while the BFS takes at
+// most maxSampleSteps steps. The fit needs stableDiffs equal second differences
+// at the end of the samples, and is verified on one more sample after them.
+const (
+	minSamplePeriods = 6
+	maxSampleSteps   = 4000
+	stableDiffs      = 3
+)
+
+// extrapolate counts the plots reachable in exactly the given number of steps
+// on the infinite map. The counts are sampled every period (the least common
+// multiple of the width and height) with the same residue as steps, and are
+// extrapolated from the last samples once their second differences are
+// constant. If the period is odd, the parity of the plots alternates between
+// samples, so twice the period is tried if needed.
+func extrapolate(map_ *Map, steps int) (int64, error) {
+	period := lib.LcmSeq([]int{map_.w, map_.h})
+	n, err := extrapolateWith(map_, steps, period)
+	if err != nil && period%2 == 1 {
+		return extrapolateWith(map_, steps, 2*period)
+	}
+	return n, err
+}
+
+func extrapolateWith(map_ *Map, steps, period int) (int64, error) {
+	residue := steps % period
+	for periods := minSamplePeriods; ; periods *= 2 {
+		// Samples 0 to periods are fitted, and the next one checks the fit.
+		last := residue + (periods+1)*period
+		brute := Part2Brute(map_, min(steps, last))
+		if steps <= last {
+			return int64(brute[steps]), nil
+		}
+
+		samples := make([]int64, periods+2)
+		for k := range samples {
+			samples[k] = int64(brute[residue+k*period])
+		}
+		n, err := fitQuadratic(samples[:periods+1], (steps-residue)/period)
+		if err == nil {
+			check, _ := fitQuadratic(samples[:periods+1], periods+1)
+			if check == samples[periods+1] {
+				return n, nil
+			}
+			err = fmt.Errorf("the fit gives %d plots in %d steps instead of %d", check, last, samples[periods+1])
+		}
+		if residue+(2*periods+1)*period > maxSampleSteps {
+			return 0, fmt.Errorf("the plots reachable every %d steps do not grow quadratically: %v", period, err)
+		}
+	}
+}
+
+// fitQuadratic returns the value at k of the quadratic through the samples at
+// 0, 1, 2... once their second differences are constant. The last
+// stableDiffs second differences must be equal.
+func fitQuadratic(samples []int64, k int) (int64, error) {
+	diffs := make([]int64, len(samples)-2)
+	for i := range diffs {
+		diffs[i] = samples[i+2] - 2*samples[i+1] + samples[i]
+	}
+	start := len(diffs) - 1
+	for start > 0 && diffs[start-1] == diffs[len(diffs)-1] {
+		start--
+	}
+	if len(diffs)-start < stableDiffs {
+		return 0, fmt.Errorf("second differences are %v", diffs)
+	}
+
+	a, b, d := samples[start], samples[start+1]-samples[start], diffs[start]
+	t := int64(k - start)
+	return a + b*t + d*t*(t-1)/2, nil
+}
+
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[21] = &sol{}
+	registry.Register(solutions.Year, 21, &sol{}, registry.Meta{
+		Title: "Step Counter",
+		Tags:  []registry.Tag{registry.Grid, registry.Math},
+	})
 }
@@ -0,0 +1,76 @@
+package day21
+
+import (
+	"strings"
+	"testing"
+)
+
+const example = `...........
+.....###.#.
+.###.##..#.
+..#.#...#..
+....#.#....
+.##..S####.
+.##..#...#.
+.......##..
+.##.#.####.
+.##..##.##.
+...........
+`
+
+func mustReadMap(t *testing.T, s string) *Map {
+	t.Helper()
+	m, err := readMap(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestReachable(t *testing.T) {
+	if got := len(reachable(mustReadMap(t, example), 6)); got != 16 {
+		t.Errorf("got %d, expected 16", got)
+	}
+}
+
+func TestExtrapolate(t *testing.T) {
+	tests := []struct {
+		name  string
+		map_  string
+		steps int
+		want  int64
+	}{
+		{"example", example, 100, 6536},
+		{"example", example, 500, 167004},
+		// Non-square maps, where the second differences settle late.
+		{"5x3", "....#\n..#.S\n#..#.\n", 427, 107856},
+		{"8x3", "S..#....\n....#..#\n.......#\n", 503, 200075},
+		{"10x3", "..#......S\n####.####.\n.....#.##.\n", 428, 106113},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extrapolate(mustReadMap(t, tt.map_), tt.steps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d in %d steps, expected %d", got, tt.steps, tt.want)
+			}
+		})
+	}
+}
+
+// The start is walled in, with 2 plots reachable in an even number of steps,
+// and 1 in an odd number. The period is odd, so the counts alternate between
+// samples.
+func TestExtrapolateNotQuadratic(t *testing.T) {
+	m := mustReadMap(t, "#####\n#S..#\n#####\n")
+	_, err := extrapolateWith(m, 10000, 15)
+	if err == nil || !strings.Contains(err.Error(), "do not grow quadratically") {
+		t.Errorf("got error %v, expected no quadratic growth", err)
+	}
+
+	if got, err := extrapolate(m, 10000); err != nil || got != 2 {
+		t.Errorf("got %d, %v with twice the period, expected 2", got, err)
+	}
+}
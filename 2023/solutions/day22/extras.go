@@ -0,0 +1,65 @@
+package day22
+
+import (
+	"fmt"
+
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+// drawStack draws the settled bricks. In part 1, the bricks that can be safely
+// disintegrated are green and the others red. In part 2, bricks are colored by
+// the number of other bricks that would fall if they were disintegrated.
+func drawStack(bricks []BrickSupports, part int) *viz.Boxes {
+	nFalling := make([]int, len(bricks))
+	maxN, nSafe := 0, 0
+	for i := range bricks {
+		nFalling[i] = findNFalling(&bricks[i])
+		maxN = max(maxN, nFalling[i])
+		if nFalling[i] == 0 {
+			nSafe++
+		}
+	}
+
+	ret := &viz.Boxes{Boxes: make([]viz.Box, len(bricks))}
+	if part == 1 {
+		ret.Caption = fmt.Sprintf("%d of %d bricks are safe", nSafe, len(bricks))
+	} else {
+		ret.Caption = fmt.Sprintf("up to %d bricks falling", maxN)
+	}
+	for i, b := range bricks {
+		box := viz.Box{Name: fmt.Sprintf("brick%d_falling%d", i, nFalling[i])}
+		for axis := range 3 {
+			lo, hi := b.Brick[0].X, b.Brick[1].X
+			switch axis {
+			case 1:
+				lo, hi = b.Brick[0].Y, b.Brick[1].Y
+			case 2:
+				lo, hi = b.Brick[0].Z, b.Brick[1].Z
+			}
+			box.Min[axis], box.Max[axis] = min(lo, hi), max(lo, hi)+1
+		}
+		switch {
+		case part == 1 && nFalling[i] == 0:
+			box.Color = viz.Green
+		case part == 1:
+			box.Color = viz.Red
+		case nFalling[i] == 0:
+			box.Color = viz.Gray
+		default:
+			box.Color = viz.Heat(float64(nFalling[i]) / float64(maxN))
+		}
+		ret.Boxes[i] = box
+	}
+	return ret
+}
+
+// Visualize draws the settled stack of bricks, as an isometric image or a 3D
+// model.
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	bricks, err := readInput(s.input)
+	if err != nil {
+		return nil, err
+	}
+	dropBricks(bricks)
+	return []viz.Figure{{Name: "stack", Scene: drawStack(bricks, part)}}, nil
+}
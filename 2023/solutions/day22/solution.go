@@ -13,6 +13,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -78,6 +79,29 @@ func dropBricks(bricks []BrickSupports) {
 	}
 }
 
+// findNFalling counts the other bricks that would fall if node was
+// disintegrated.
+func findNFalling(node *BrickSupports) int {
+	fallen := lib.NewSet(node)
+	queue := []*BrickSupports{node}
+	for ; len(queue) > 0; queue = queue[1:] {
+		cur := queue[0]
+		for sb := range cur.supports {
+			wouldFall := true
+			for spb := range sb.supportedBy {
+				if !fallen.Has(spb) {
+					wouldFall = false
+				}
+			}
+			if wouldFall {
+				queue = append(queue, sb)
+				fallen.Add(sb)
+			}
+		}
+	}
+	return len(fallen) - 1
+}
+
 func (s *sol) SolvePart1() (string, error) {
 	bricks, err := readInput(s.input)
 	if err != nil {
@@ -118,27 +142,6 @@ func (s *sol) SolvePart2() (string, error) {
 
 	dropBricks(bricks)
 
-	findNFalling := func(node *BrickSupports) int {
-		fallen := lib.NewSet(node)
-		queue := []*BrickSupports{node}
-		for ; len(queue) > 0; queue = queue[1:] {
-			cur := queue[0]
-			for sb := range cur.supports {
-				wouldFall := true
-				for spb := range sb.supportedBy {
-					if !fallen.Has(spb) {
-						wouldFall = false
-					}
-				}
-				if wouldFall {
-					queue = append(queue, sb)
-					fallen.Add(sb)
-				}
-			}
-		}
-		return len(fallen) - 1
-	}
-
 	inputCh := make(chan *BrickSupports, 100)
 	cnt := 0
 	cntL := &sync.Mutex{}
@@ -163,9 +166,8 @@ func (s *sol) SolvePart2() (string, error) {
 	return strconv.FormatInt(int64(cnt), 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func readInput(input io.Reader) ([]BrickSupports, error) {
@@ -203,5 +205,8 @@ func readInput(input io.Reader) ([]BrickSupports, error) {
 }
 
 func init() {
-	solutions.Days[22] = &sol{}
+	registry.Register(solutions.Year, 22, &sol{}, registry.Meta{
+		Title: "Sand Slabs",
+		Tags:  []registry.Tag{registry.Simulation, registry.Graph},
+	})
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -59,11 +60,13 @@ func (s *sol) SolvePart2() (string, error) {
 	return strconv.FormatInt(int64(l), 10), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[23] = &sol{}
+	registry.Register(solutions.Year, 23, &sol{}, registry.Meta{
+		Title: "A Long Walk",
+		Tags:  []registry.Tag{registry.Grid, registry.Graph},
+	})
 }
@@ -1,9 +1,11 @@
 // Solution for https://adventofcode.com/2023/day/24
 package day24
 
+// Everything is computed exactly with big.Int and big.Rat, as the coordinates
+// are too large for float64.
+//
 // Part 2: Find the system of linear equations, then use Gauss–Jordan
-// elimination to solve it.
-// Note: float64 does not provide enough precision for the calculations.
+// elimination to solve it. The result is checked against every hailstone.
 
 import (
 	"bufio"
@@ -11,43 +13,45 @@ import (
 	"io"
 	"math/big"
 	"os"
-	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/kanna5/advent_of_code/2023/lib"
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
 	input io.Reader
 }
 
+// crossInFutureXY tells whether the paths of two hailstones, ignoring the Z
+// axis, cross inside the given box at positive times for both.
 func crossInFutureXY(h1, h2 *Hailstone, rangeMin, rangeMax int64) bool {
-	getAB := func(h *Hailstone) (float64, float64) {
-		p, v := h.Pos.Vec2(), h.Vel.Vec2()
-		a := float64(v.Y) / float64(v.X)
-		b := float64(p.Y) - (a * float64(p.X))
-		return a, b
-	}
-	a1, b1 := getAB(h1)
-	a2, b2 := getAB(h2)
+	p1, v1 := h1.Pos.big(), h1.Vel.big()
+	p2, v2 := h2.Pos.big(), h2.Vel.big()
 
-	if a1 == a2 {
-		return false
+	// p1 + t*v1 = p2 + s*v2; crossing both sides with v1 or v2 gives t and s.
+	det := cross2(v1, v2)
+	if det.Sign() == 0 {
+		return false // parallel
 	}
-
-	x := (b2 - b1) / (a1 - a2)
-	y := a1*x + b1
-
-	if x < float64(rangeMin) || x > float64(rangeMax) ||
-		y < float64(rangeMin) || y > float64(rangeMax) {
+	d := sub(p2, p1)
+	t := new(big.Rat).SetFrac(cross2(d, v2), det)
+	s := new(big.Rat).SetFrac(cross2(d, v1), det)
+	if t.Sign() <= 0 || s.Sign() <= 0 {
 		return false
 	}
 
-	d1 := (x - float64(h1.Pos.X)) * float64(h1.Vel.X)
-	d2 := (x - float64(h2.Pos.X)) * float64(h2.Vel.X)
-	return d1 > 0 && d2 > 0
+	lo, hi := new(big.Rat).SetInt64(rangeMin), new(big.Rat).SetInt64(rangeMax)
+	for _, axis := range []int{0, 1} {
+		at := new(big.Rat).Mul(t, new(big.Rat).SetInt(v1[axis]))
+		at.Add(at, new(big.Rat).SetInt(p1[axis]))
+		if at.Cmp(lo) < 0 || at.Cmp(hi) > 0 {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *sol) SolvePart1() (string, error) {
@@ -71,59 +75,122 @@ func (s *sol) SolvePart1() (string, error) {
 	return fmt.Sprint(cnt), nil
 }
 
-func toParams(x1, y1, a1, b1, x2, y2, a2, b2 int64) []*big.Float {
-	// (b1-b2)x + (a2-a1)y + (y2-y1)a + (x1-x2)b = x1*b1 - y1*a1 - x2*b2 + y2*a2
-	return []*big.Float{
-		new(big.Float).SetInt64(b1 - b2), new(big.Float).SetInt64(a2 - a1),
-		new(big.Float).SetInt64(y2 - y1), new(big.Float).SetInt64(x1 - x2),
-		new(big.Float).SetInt64(x1*b1 - y1*a1 - x2*b2 + y2*a2),
+// equations returns the rows of the linear system for the rock's position P
+// and velocity V, with unknowns ordered (Px, Py, Pz, Vx, Vy, Vz).
+//
+// The rock hits hailstone i iff (P - p_i) × (V - v_i) = 0. Subtracting the
+// equations of two hailstones cancels the non-linear P × V term:
+//
+//	P × (v_j - v_i) + (p_j - p_i) × V = p_j × v_j - p_i × v_i
+func equations(hi, hj *Hailstone) [][]*big.Rat {
+	pi, vi, pj, vj := hi.Pos.big(), hi.Vel.big(), hj.Pos.big(), hj.Vel.big()
+	w, u := sub(vj, vi), sub(pj, pi)
+	rhs := sub(cross(pj, vj), cross(pi, vi))
+
+	neg := func(n *big.Int) *big.Int { return new(big.Int).Neg(n) }
+	zero := new(big.Int)
+	rows := [][]*big.Int{
+		{zero, w[2], neg(w[1]), zero, neg(u[2]), u[1], rhs[0]},
+		{neg(w[2]), zero, w[0], u[2], zero, neg(u[0]), rhs[1]},
+		{w[1], neg(w[0]), zero, neg(u[1]), u[0], zero, rhs[2]},
 	}
+	ret := make([][]*big.Rat, len(rows))
+	for i, row := range rows {
+		ret[i] = make([]*big.Rat, len(row))
+		for j, n := range row {
+			ret[i][j] = new(big.Rat).SetInt(n)
+		}
+	}
+	return ret
 }
 
-func calc(m [][]*big.Float) ([]*big.Float, bool) {
-	getNZeros := func(a []*big.Float) int {
-		i := 0
-		for ; i < len(a) && a[i].Sign() == 0; i++ {
+// solve runs Gauss–Jordan elimination on an augmented n×(n+1) matrix in place.
+// It returns false if the system has no unique solution.
+func solve(m [][]*big.Rat) ([]*big.Rat, bool) {
+	n := len(m)
+	for i := range n {
+		p := i
+		for p < n && m[p][i].Sign() == 0 {
+			p++
 		}
-		return i
-	}
-	cmp := func(a, b []*big.Float) int { return getNZeros(a) - getNZeros(b) }
-	normalize := func(a []*big.Float) {
-		i := getNZeros(a)
-		if i < len(a) {
-			first := new(big.Float).Copy(a[i])
-			for j := i; j < len(a); j++ {
-				a[j].Quo(a[j], first)
+		if p == n {
+			return nil, false
+		}
+		m[i], m[p] = m[p], m[i]
+
+		pivot := new(big.Rat).Set(m[i][i])
+		for j := i; j <= n; j++ {
+			m[i][j].Quo(m[i][j], pivot)
+		}
+		for k := range n {
+			if k == i || m[k][i].Sign() == 0 {
+				continue
+			}
+			f := new(big.Rat).Set(m[k][i])
+			for j := i; j <= n; j++ {
+				m[k][j].Sub(m[k][j], new(big.Rat).Mul(f, m[i][j]))
 			}
 		}
 	}
-	sub := func(a, b []*big.Float, i int) {
-		if b[i].Sign() == 0 {
-			return
-		}
-		mul := new(big.Float).Quo(a[i], b[i])
-		for i := range a {
-			a[i].Sub(a[i], new(big.Float).Mul(b[i], mul))
+	ret := make([]*big.Rat, n)
+	for i := range n {
+		ret[i] = m[i][n]
+	}
+	return ret, true
+}
+
+// findRock derives the rock's trajectory from the first three hailstones that
+// give an independent system of equations.
+func findRock(stones []Hailstone) (*Hailstone, error) {
+	for i := range stones {
+		for j := i + 1; j < len(stones); j++ {
+			for k := j + 1; k < len(stones); k++ {
+				m := append(equations(&stones[i], &stones[j]), equations(&stones[i], &stones[k])...)
+				ans, ok := solve(m)
+				if !ok {
+					continue
+				}
+				var v [6]int64
+				for n, r := range ans {
+					if !r.IsInt() || !r.Num().IsInt64() {
+						return nil, fmt.Errorf("the rock's trajectory is not integral: %v", ans)
+					}
+					v[n] = r.Num().Int64()
+				}
+				return &Hailstone{Pos: Vec3{v[0], v[1], v[2]}, Vel: Vec3{v[3], v[4], v[5]}}, nil
+			}
 		}
 	}
+	return nil, fmt.Errorf("the hailstones do not determine a unique trajectory for the rock")
+}
 
-	for i := range 4 {
-		slices.SortStableFunc(m[i:], cmp)
-		if m[i][i].Sign() == 0 {
-			return nil, false // no unique solution
+// collisionTime returns the time the rock hits the hailstone, which must be a
+// non-negative integer.
+func collisionTime(rock, h *Hailstone) (int64, error) {
+	rp, rv, hp, hv := rock.Pos.big(), rock.Vel.big(), h.Pos.big(), h.Vel.big()
+	var t *big.Rat
+	for axis := range 3 {
+		dp := new(big.Int).Sub(hp[axis], rp[axis])
+		dv := new(big.Int).Sub(rv[axis], hv[axis])
+		if dv.Sign() == 0 {
+			if dp.Sign() != 0 {
+				return 0, fmt.Errorf("the rock never hits hailstone %v", *h)
+			}
+			continue
 		}
-		normalize(m[i])
-		for j := i + 1; j < 4; j++ {
-			sub(m[j], m[i], i)
+		at := new(big.Rat).SetFrac(dp, dv)
+		if t != nil && t.Cmp(at) != 0 {
+			return 0, fmt.Errorf("the rock misses hailstone %v", *h)
 		}
+		t = at
 	}
-	// Back substitution
-	for i := 1; i < 4; i++ {
-		for j := 0; j < i; j++ {
-			sub(m[j], m[i], i)
-		}
+	if t == nil {
+		return 0, fmt.Errorf("the rock travels along hailstone %v", *h)
 	}
-	return []*big.Float{m[0][4], m[1][4], m[2][4], m[3][4]}, true
+	if !t.IsInt() || t.Sign() < 0 {
+		return 0, fmt.Errorf("the rock hits hailstone %v at time %v", *h, t.RatString())
+	}
+	return t.Num().Int64(), nil
 }
 
 func (s *sol) SolvePart2() (string, error) {
@@ -131,41 +198,20 @@ func (s *sol) SolvePart2() (string, error) {
 	if err != nil {
 		return "", err
 	}
-
-	// solve x, y
-	matrix := [][]*big.Float{}
-	s1 := &stones[0]
-	for i := 1; i <= 4; i++ {
-		s2 := &stones[i]
-		matrix = append(matrix, toParams(
-			s1.Pos.X, s1.Pos.Y, s1.Vel.X, s1.Vel.Y,
-			s2.Pos.X, s2.Pos.Y, s2.Vel.X, s2.Vel.Y,
-		))
-	}
-	ans1, ok1 := calc(matrix)
-
-	// solve x, z
-	matrix = [][]*big.Float{}
-	for i := 1; i <= 4; i++ {
-		s2 := &stones[i]
-		matrix = append(matrix, toParams(
-			s1.Pos.X, s1.Pos.Z, s1.Vel.X, s1.Vel.Z,
-			s2.Pos.X, s2.Pos.Z, s2.Vel.X, s2.Vel.Z,
-		))
-	}
-	ans2, ok2 := calc(matrix)
-	if ok1 && ok2 {
-		num := new(big.Float).Add(ans1[0], ans1[1])
-		num.Add(num, ans2[1])
-		numI, _ := num.Int64()
-		return strconv.FormatInt(numI, 10), nil
-	}
-	return "", fmt.Errorf("could not find an solution with chosen hailstones")
+	rock, err := findRock(stones)
+	if err != nil {
+		return "", err
+	}
+	for i := range stones {
+		if _, err := collisionTime(rock, &stones[i]); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprint(rock.Pos.X + rock.Pos.Y + rock.Pos.Z), nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func readInput(input io.Reader) ([]Hailstone, error) {
@@ -220,5 +266,8 @@ func getArgs(defaultMin, defaultMax int64) (rangeMin, rangeMax int64, err error)
 }
 
 func init() {
-	solutions.Days[24] = &sol{}
+	registry.Register(solutions.Year, 24, &sol{}, registry.Meta{
+		Title: "Never Tell Me The Odds",
+		Tags:  []registry.Tag{registry.Math, registry.Geometry},
+	})
 }
@@ -0,0 +1,102 @@
+package day24
+
+import (
+	"strings"
+	"testing"
+)
+
+const example = `19, 13, 30 @ -2, 1, -2
+18, 19, 22 @ -1, -1, -2
+20, 25, 34 @ -2, -2, -4
+12, 31, 28 @ -1, -2, -1
+20, 19, 15 @ 1, -5, -3
+`
+
+func TestSolvePart1(t *testing.T) {
+	t.Setenv("RANGE_MIN", "7")
+	t.Setenv("RANGE_MAX", "27")
+	s := &sol{}
+	s.WithInput(strings.NewReader(example))
+	got, err := s.SolvePart1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "2" {
+		t.Errorf("got %q, expected %q", got, "2")
+	}
+}
+
+func TestCrossInFutureXY(t *testing.T) {
+	diagonal := &Hailstone{Vel: Vec3{3, 3, 0}} // y = x
+	tests := []struct {
+		name     string
+		h        Hailstone
+		min, max int64
+		want     bool
+	}{
+		{"on the edge", Hailstone{Vec3{0, 81, 0}, Vec3{1, -2, 0}}, 7, 27, true},
+		{"a third past the edge", Hailstone{Vec3{0, 82, 0}, Vec3{1, -2, 0}}, 7, 27, false},
+		{"a third inside", Hailstone{Vec3{0, 82, 0}, Vec3{1, -2, 0}}, 7, 28, true},
+		{"in the past", Hailstone{Vec3{81, 0, 0}, Vec3{-1, 2, 0}}, 7, 27, false},
+		{"parallel", Hailstone{Vec3{0, 1, 0}, Vec3{1, 1, 0}}, -100, 100, false},
+		{
+			"large",
+			Hailstone{Vec3{0, 900000000000001, 0}, Vec3{1, -2, 0}},
+			200000000000000, 300000000000000,
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := crossInFutureXY(diagonal, &tt.h, tt.min, tt.max); got != tt.want {
+				t.Errorf("got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolvePart2(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		err   string
+	}{
+		{name: "example", input: example, want: "47"},
+		// The rock starts at 24, 13, 10 with velocity -3, 1, 2.
+		{name: "never hits", input: example + "27, 15, 8 @ -3, 1, 2\n", err: "the rock never hits"},
+		{name: "misses", input: example + "25, 14, 11 @ -5, -1, 1\n", err: "the rock misses"},
+		{name: "along", input: example + "24, 13, 10 @ -3, 1, 2\n", err: "the rock travels along"},
+		{name: "in the past", input: example + "27, 12, 8 @ 0, 0, 0\n", err: "at time -1"},
+		{name: "half time", input: example + "25, 14, 11 @ -5, -1, 0\n", err: "at time 1/2"},
+		{
+			name:  "not integral",
+			input: "1, 1, 1 @ 0, 1, 2\n2, 5, 2 @ -1, 1, 2\n3, -2, 8 @ -1, 3, 0\n",
+			err:   "the rock's trajectory is not integral",
+		},
+		{
+			name:  "parallel",
+			input: "0, 0, 0 @ 1, 1, 1\n1, 1, 1 @ 1, 1, 1\n2, 2, 2 @ 1, 1, 1\n",
+			err:   "the hailstones do not determine a unique trajectory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sol{}
+			s.WithInput(strings.NewReader(tt.input))
+			got, err := s.SolvePart2()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, expected %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package day24
 
+import "math/big"
+
 type Vec2 struct{ X, Y int64 }
 type Vec3 struct{ X, Y, Z int64 }
 
@@ -9,3 +11,32 @@ type Hailstone struct {
 	Pos Vec3
 	Vel Vec3
 }
+
+type bigVec [3]*big.Int
+
+func (v *Vec3) big() bigVec {
+	return bigVec{big.NewInt(v.X), big.NewInt(v.Y), big.NewInt(v.Z)}
+}
+
+func sub(a, b bigVec) bigVec {
+	var ret bigVec
+	for i := range ret {
+		ret[i] = new(big.Int).Sub(a[i], b[i])
+	}
+	return ret
+}
+
+func mul(a, b *big.Int) *big.Int { return new(big.Int).Mul(a, b) }
+
+// cross2 returns the cross product of a and b projected on the XY plane.
+func cross2(a, b bigVec) *big.Int {
+	return new(big.Int).Sub(mul(a[0], b[1]), mul(a[1], b[0]))
+}
+
+func cross(a, b bigVec) bigVec {
+	return bigVec{
+		new(big.Int).Sub(mul(a[1], b[2]), mul(a[2], b[1])),
+		new(big.Int).Sub(mul(a[2], b[0]), mul(a[0], b[2])),
+		cross2(a, b),
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"sync"
 
 	"github.com/kanna5/advent_of_code/2023/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -110,13 +111,16 @@ func (s *sol) SolvePart2() (string, error) {
 	return "👉🔴 🎉❄️🎄⭐️😊", nil
 }
 
-func (s *sol) WithInput(i io.Reader) solutions.Solver {
+func (s *sol) WithInput(i io.Reader) {
 	s.input = i
-	return s
 }
 
 func init() {
-	solutions.Days[25] = &sol{}
+	registry.Register(solutions.Year, 25, &sol{}, registry.Meta{
+		Title:   "Snowverload",
+		Tags:    []registry.Tag{registry.Graph},
+		NoPart2: true,
+	})
 }
 
 func readInput(input io.Reader) ([]string, []Edge, error) {
@@ -1,13 +1,9 @@
 package solutions
 
-import "io"
+import "github.com/kanna5/advent_of_code/common/registry"
 
-type Solver interface {
-	WithInput(i io.Reader) Solver
-	SolvePart1() (string, error)
-	SolvePart2() (string, error)
-}
+const Year = 2023
 
-var (
-	Days [26]Solver // Use index 1 ~ 25
-)
+func init() {
+	registry.RegisterYear(Year, 25)
+}
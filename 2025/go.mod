@@ -4,6 +4,7 @@ go 1.25
 
 require (
 	github.com/k0kubun/pp/v3 v3.5.0
+	github.com/kanna5/advent_of_code/common v0.0.0
 	golang.org/x/exp v0.0.0-20251125195548-87e1e737ad39
 )
 
@@ -13,3 +14,5 @@ require (
 	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/text v0.26.0 // indirect
 )
+
+replace github.com/kanna5/advent_of_code/common => ../common
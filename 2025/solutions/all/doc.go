@@ -0,0 +1,2 @@
+// Package all registers every day of 2025 on import.
+package all
@@ -10,6 +10,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2025/lib"
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -103,5 +104,8 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[1] = &sol{}
+	registry.Register(solutions.Year, 1, &sol{}, registry.Meta{
+		Title: "Secret Entrance",
+		Tags:  []registry.Tag{registry.Simulation},
+	})
 }
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -110,5 +111,8 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[2] = &sol{}
+	registry.Register(solutions.Year, 2, &sol{}, registry.Meta{
+		Title: "Gift Shop",
+		Tags:  []registry.Tag{registry.Math},
+	})
 }
@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -90,5 +91,7 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[3] = &sol{}
+	registry.Register(solutions.Year, 3, &sol{}, registry.Meta{
+		Title: "Lobby",
+	})
 }
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -66,5 +67,8 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[4] = &sol{}
+	registry.Register(solutions.Year, 4, &sol{}, registry.Meta{
+		Title: "Printing Department",
+		Tags:  []registry.Tag{registry.Grid, registry.Simulation},
+	})
 }
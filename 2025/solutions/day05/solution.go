@@ -10,6 +10,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2025/lib"
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -59,7 +60,9 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[5] = &sol{}
+	registry.Register(solutions.Year, 5, &sol{}, registry.Meta{
+		Title: "Cafeteria",
+	})
 }
 
 func readInput(input io.Reader) ([]Range, []int, error) {
@@ -10,6 +10,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2025/lib"
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -147,5 +148,8 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[6] = &sol{}
+	registry.Register(solutions.Year, 6, &sol{}, registry.Meta{
+		Title: "Trash Compactor",
+		Tags:  []registry.Tag{registry.Grid},
+	})
 }
@@ -0,0 +1,112 @@
+package day07
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+// beamColor colors beams by their number of timelines, on a logarithmic scale.
+func beamColor(timelines, maxTimelines int) viz.Cell {
+	return viz.Cell{Ch: '|', FG: viz.Heat(math.Log(float64(timelines)) / math.Log(float64(max(maxTimelines, 2))))}
+}
+
+// replay animates the beams going down the manifold, one row per frame.
+// Splitters hit by a beam are highlighted. In part 2, beams are colored by
+// their number of timelines.
+func replay(layout *Layout, part int) (*viz.Animation, map[int]int) {
+	rows := make([]map[int]int, len(layout.splitters))
+	hits := make([][]int, len(layout.splitters))
+	beams := map[int]int{layout.startPos: 1}
+	maxTimelines := 1
+	for row := range layout.splitters {
+		if row > 0 {
+			beams, hits[row] = split(beams, layout.splitters[row])
+		}
+		rows[row] = beams
+		for _, val := range beams {
+			maxTimelines = max(maxTimelines, val)
+		}
+	}
+
+	g := viz.NewGrid(layout.width, len(layout.splitters))
+	for row, spltrs := range layout.splitters {
+		for _, pos := range spltrs {
+			g.Set(pos, row, viz.Cell{Ch: '^', FG: viz.Gray})
+		}
+	}
+	g.Set(layout.startPos, 0, viz.Cell{Ch: 'S', FG: viz.White, BG: viz.Green})
+
+	anim := &viz.Animation{Delay: 5}
+	nSplt := 0
+	for row := range layout.splitters {
+		for _, pos := range hits[row] {
+			g.Set(pos, row, viz.Cell{Ch: '^', FG: viz.White, BG: viz.Red})
+		}
+		nSplt += len(hits[row])
+		total := 0
+		for pos, val := range rows[row] {
+			total += val
+			if row == 0 || !g.Contains(pos, row) {
+				continue
+			}
+			cell := viz.Cell{Ch: '|', FG: viz.Cyan}
+			if part == 2 {
+				cell = beamColor(val, maxTimelines)
+			}
+			g.Set(pos, row, cell)
+		}
+
+		frame := g.Clone()
+		if part == 1 {
+			frame.Caption = fmt.Sprintf("row %d: %d splits", row, nSplt)
+		} else {
+			frame.Caption = fmt.Sprintf("row %d: %d timelines", row, total)
+		}
+		anim.Frames = append(anim.Frames, frame)
+	}
+	anim.Delays = make([]int, len(anim.Frames))
+	anim.Delays[len(anim.Delays)-1] = 300
+	return anim, rows[len(rows)-1]
+}
+
+// Height of the bars showing the number of timelines by column.
+const barHeight = 16
+
+// drawTimelines draws the number of timelines ending in each column as bars.
+func drawTimelines(width int, timelines map[int]int) *viz.Grid {
+	total, maxTimelines := 0, 1
+	for _, val := range timelines {
+		total += val
+		maxTimelines = max(maxTimelines, val)
+	}
+	g := viz.NewGrid(width, barHeight)
+	g.Caption = fmt.Sprintf("%d timelines, up to %d in a column", total, maxTimelines)
+	for pos, val := range timelines {
+		if !g.Contains(pos, 0) {
+			continue
+		}
+		h := (val*barHeight + maxTimelines - 1) / maxTimelines
+		for y := barHeight - h; y < barHeight; y++ {
+			g.Set(pos, y, viz.Cell{Ch: '█', FG: viz.Heat(float64(val) / float64(maxTimelines))})
+		}
+	}
+	return g
+}
+
+// Visualize replays the beams row by row. The replay is printed frame by frame
+// in the terminal, or written as a GIF. In part 2, the number of timelines by
+// column is also drawn.
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	layout, err := readInput(s.input)
+	if err != nil {
+		return nil, err
+	}
+	anim, timelines := replay(layout, part)
+	figs := []viz.Figure{{Name: "replay", Scene: anim}}
+	if part == 2 {
+		figs = append(figs, viz.Figure{Name: "timelines", Scene: drawTimelines(layout.width, timelines)})
+	}
+	return figs, nil
+}
@@ -7,12 +7,31 @@ import (
 	"slices"
 	"strconv"
 
-	"github.com/kanna5/advent_of_code/2025/lib"
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct{ input io.Reader }
 
+// split moves the beams, mapped to the number of timelines by their positions,
+// through a row of splitters. It returns the new beams and the positions of
+// the splitters hit.
+func split(beams map[int]int, splitters []int) (map[int]int, []int) {
+	newBeams := make(map[int]int, len(beams)+len(splitters))
+	hit := []int{}
+	for pos, val := range beams {
+		if slices.Contains(splitters, pos) {
+			hit = append(hit, pos)
+			newBeams[pos-1] += val
+			newBeams[pos+1] += val
+		} else {
+			newBeams[pos] += val
+		}
+	}
+	slices.Sort(hit)
+	return newBeams, hit
+}
+
 func (s *sol) SolvePart1() (string, error) {
 	layout, err := readInput(s.input)
 	if err != nil {
@@ -20,24 +39,14 @@ func (s *sol) SolvePart1() (string, error) {
 	}
 
 	nSplt := 0
-	beams := []int{layout.startPos}
+	beams := map[int]int{layout.startPos: 1}
 	for row := range layout.splitters {
 		if len(layout.splitters[row]) == 0 {
 			continue
 		}
-		newBeams := lib.NewSet[int]()
-		for _, pos := range beams {
-			if slices.Contains(layout.splitters[row], pos) {
-				nSplt++
-				newBeams.Add(pos-1, pos+1)
-			} else {
-				newBeams.Add(pos)
-			}
-		}
-		beams = beams[:0]
-		for p := range newBeams {
-			beams = append(beams, p)
-		}
+		var hit []int
+		beams, hit = split(beams, layout.splitters[row])
+		nSplt += len(hit)
 	}
 
 	return strconv.FormatInt(int64(nSplt), 10), nil
@@ -54,16 +63,7 @@ func (s *sol) SolvePart2() (string, error) {
 		if len(layout.splitters[row]) == 0 {
 			continue
 		}
-		newBeams := map[int]int{}
-		for pos, val := range beams {
-			if slices.Contains(layout.splitters[row], pos) {
-				newBeams[pos-1] += val
-				newBeams[pos+1] += val
-			} else {
-				newBeams[pos] += val
-			}
-		}
-		beams = newBeams
+		beams, _ = split(beams, layout.splitters[row])
 	}
 
 	total := 0
@@ -75,7 +75,7 @@ func (s *sol) SolvePart2() (string, error) {
 }
 
 func readInput(input io.Reader) (*Layout, error) {
-	startPos := 0
+	startPos, width := 0, 0
 	splitters := [][]int{}
 
 	sc := bufio.NewScanner(input)
@@ -85,6 +85,7 @@ func readInput(input io.Reader) (*Layout, error) {
 			break
 		}
 
+		width = max(width, len(line))
 		spltrs := []int{} // splitter position
 		for i := range line {
 			switch line[i] {
@@ -104,6 +105,7 @@ func readInput(input io.Reader) (*Layout, error) {
 
 	return &Layout{
 		startPos:  startPos,
+		width:     width,
 		splitters: splitters,
 	}, nil
 }
@@ -113,5 +115,8 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[7] = &sol{}
+	registry.Register(solutions.Year, 7, &sol{}, registry.Meta{
+		Title: "Laboratories",
+		Tags:  []registry.Tag{registry.Grid, registry.Simulation},
+	})
 }
@@ -2,5 +2,6 @@ package day07
 
 type Layout struct {
 	startPos  int
+	width     int
 	splitters [][]int
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2025/lib"
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -202,5 +203,8 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[8] = &sol{}
+	registry.Register(solutions.Year, 8, &sol{}, registry.Meta{
+		Title: "Playground",
+		Tags:  []registry.Tag{registry.Graph, registry.Geometry},
+	})
 }
@@ -0,0 +1,168 @@
+package day09
+
+import (
+	"fmt"
+	"slices"
+)
+
+// The edges of the polygon, walked clockwise, only tell why a rectangle is
+// outside when drawing the candidates: an edge of the polygon cuts through it,
+// or overlays one of its edges in the opposite direction. These rules miss some
+// rectangles outside, which Tiles finds.
+
+type Dir uint8
+
+const (
+	Up Dir = iota
+	Right
+	Down
+	Left
+	InvalidDirection
+)
+
+func (d Dir) TurnRight() Dir { return (d + 1) % 4 }
+func (d Dir) TurnLeft() Dir  { return (d + 3) % 4 }
+func (d Dir) Flip() Dir      { return (d + 2) % 4 }
+
+func (c *Coord) LineWith(another *Coord) *Line {
+	ret := &Line{Points: [2]Coord{*c, *another}}
+	switch {
+	case another.X > c.X:
+		ret.Dir = Right
+	case another.X < c.X:
+		ret.Dir = Left
+	case another.Y > c.Y:
+		ret.Dir = Down
+	case another.Y < c.Y:
+		ret.Dir = Up
+	default:
+		ret.Dir = InvalidDirection
+	}
+	return ret
+}
+
+type Line struct {
+	Points [2]Coord
+	Dir    Dir
+}
+
+func (l *Line) Flip() *Line {
+	return &Line{
+		Points: [2]Coord{l.Points[1], l.Points[0]},
+		Dir:    l.Dir.Flip(),
+	}
+}
+
+func (l *Line) ContainsPoint(p *Coord) bool {
+	a, b := &l.Points[0], &l.Points[1]
+	return p.X >= min(a.X, b.X) &&
+		p.X <= max(a.X, b.X) &&
+		p.Y >= min(a.Y, b.Y) &&
+		p.Y <= max(a.Y, b.Y)
+}
+
+func (l *Line) Overlays(another *Line) bool {
+	p0, p1 := &l.Points[0], &l.Points[1]
+	p2, p3 := &another.Points[0], &another.Points[1]
+	var amin, amax, bmin, bmax int
+
+	if p0.X == p1.X && p0.X == p2.X && p0.X == p3.X {
+		amin, amax = min(p0.Y, p1.Y), max(p0.Y, p1.Y)
+		bmin, bmax = min(p2.Y, p3.Y), max(p2.Y, p3.Y)
+	} else if p0.Y == p1.Y && p0.Y == p2.Y && p0.Y == p3.Y {
+		amin, amax = min(p0.X, p1.X), max(p0.X, p1.X)
+		bmin, bmax = min(p2.X, p3.X), max(p2.X, p3.X)
+	} else {
+		return false
+	}
+	if amax <= bmin || amin >= bmax {
+		return false
+	}
+	return true
+}
+
+func (l *Line) Cuts(another *Line) bool {
+	if l.Dir.TurnLeft() != another.Dir {
+		return false
+	}
+	p0, p1 := &l.Points[0], &l.Points[1]
+	p2, p3 := &another.Points[0], &another.Points[1]
+
+	var (
+		l0Axis0, l0Axis1Min, l0Axis1Max,
+		l1Axis0Min, l1Axis0Max, l1Axis1 int
+	)
+	if p0.X == p1.X {
+		l0Axis0, l0Axis1Min, l0Axis1Max = p0.X, min(p0.Y, p1.Y), max(p0.Y, p1.Y)
+		l1Axis0Min, l1Axis0Max, l1Axis1 = min(p2.X, p3.X), max(p2.X, p3.X), p2.Y
+	} else {
+		l0Axis0, l0Axis1Min, l0Axis1Max = p0.Y, min(p0.X, p1.X), max(p0.X, p1.X)
+		l1Axis0Min, l1Axis0Max, l1Axis1 = min(p2.Y, p3.Y), max(p2.Y, p3.Y), p2.X
+	}
+
+	if l0Axis0 >= l1Axis0Max || l0Axis0 <= l1Axis0Min {
+		return false
+	}
+	if l0Axis1Min >= l1Axis1 || l0Axis1Max <= l1Axis1 {
+		return another.ContainsPoint(p0)
+	}
+	return true
+}
+
+func toEdges(points []Coord) ([]*Line, error) {
+	edges := make([]*Line, 0, len(points))
+	var last *Line
+	turns := 0
+	for i := range points {
+		line := points[i].LineWith(&points[(i+1)%len(points)])
+		if last != nil {
+			switch {
+			case last.Dir.TurnRight() == line.Dir:
+				turns++
+			case last.Dir.TurnLeft() == line.Dir:
+				turns--
+			default:
+				return nil, fmt.Errorf("invalid input: expected turns")
+			}
+		}
+		last = line
+		edges = append(edges, line)
+	}
+	// Make sure coordinates are connected clockwise
+	if turns < 0 {
+		slices.Reverse(edges)
+		for i := range edges {
+			edges[i] = edges[i].Flip()
+		}
+	}
+	return edges, nil
+}
+
+// rejection is the reason why a rectangle is not inside the polygon.
+type rejection uint8
+
+const (
+	rejectedOther   rejection = iota // No edge rule applies, e.g. the rectangle is a line
+	rejectedOverlay                  // A polygon edge overlays a rectangle edge in the opposite direction
+	rejectedCut                      // A polygon edge cuts through the rectangle
+)
+
+// checkRect finds the edge rule rejecting a rectangle outside the polygon.
+func checkRect(a, b Coord, edges []*Line) rejection {
+	rectE, err := toEdges(toRect(a, b))
+	if err != nil {
+		return rejectedOther
+	}
+
+	for _, e := range edges {
+		for _, rE := range rectE {
+			if e.Overlays(rE) && e.Dir != rE.Dir {
+				return rejectedOverlay
+			}
+			if e.Cuts(rE) {
+				return rejectedCut
+			}
+		}
+	}
+	return rejectedOther
+}
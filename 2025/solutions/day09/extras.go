@@ -0,0 +1,87 @@
+package day09
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+var rejectionStyles = map[rejection]struct {
+	color color.Color
+	desc  string
+}{
+	rejectedOther:   {viz.Purple, "outside"},
+	rejectedOverlay: {viz.Orange, "edge overlays in the opposite direction"},
+	rejectedCut:     {viz.Red, "edge cuts through"},
+}
+
+func drawPolygon(coords []Coord) viz.Path {
+	path := viz.Path{Closed: true, Fill: color.RGBA{0xcf, 0xe8, 0xfc, 0xff}}
+	for _, c := range coords {
+		path.Points = append(path.Points, image.Pt(c.X, c.Y))
+	}
+	return path
+}
+
+func drawRect(a, b Coord, stroke color.Color, width float64, title string) viz.Path {
+	path := viz.Path{Closed: true, Stroke: stroke, Width: width, Title: title}
+	for _, c := range toRect(a, b) {
+		path.Points = append(path.Points, image.Pt(c.X, c.Y))
+	}
+	return path
+}
+
+// Visualize draws the polygon and the largest rectangle. In part 2, it draws
+// the largest rectangle of part 1 in gray, and also draws in a separate figure
+// every candidate rejected while it was larger than the best so far, titled
+// with a tile of it outside the polygon. They are colored by the edge rule
+// rejecting them, see checkRect: orange if an edge of the polygon overlays one
+// of their edges in the opposite direction, red if an edge cuts through them,
+// purple if no rule applies.
+func (s *sol) Visualize(part int) ([]viz.Figure, error) {
+	coords, err := readInput(s.input)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b, area := largestRect(coords, func(i, j int) bool { return true })
+	if part == 1 {
+		best := &viz.Polylines{Paths: []viz.Path{
+			drawPolygon(coords),
+			drawRect(a, b, viz.Green, 3, fmt.Sprintf("area %d", area)),
+		}}
+		return []viz.Figure{{Name: "best", Scene: best}}, nil
+	}
+
+	tiles, err := newTiles(coords)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := toEdges(coords)
+	if err != nil {
+		return nil, err
+	}
+	candidates := &viz.Polylines{Paths: []viz.Path{drawPolygon(coords)}}
+	a2, b2, area2 := largestRect(coords, func(i, j int) bool {
+		out, outside := tiles.Outside(i, j)
+		if outside {
+			a, b := coords[i], coords[j]
+			style := rejectionStyles[checkRect(a, b, edges)]
+			candidates.Paths = append(candidates.Paths, drawRect(a, b, style.color, 1,
+				fmt.Sprintf("(%d,%d)-(%d,%d): %s, tile (%d,%d) is outside", a.X, a.Y, b.X, b.Y, style.desc, out.X, out.Y)))
+		}
+		return !outside
+	})
+	best := &viz.Polylines{Paths: []viz.Path{
+		drawPolygon(coords),
+		drawRect(a, b, viz.Gray, 2, fmt.Sprintf("part 1: area %d", area)),
+		drawRect(a2, b2, viz.Green, 3, fmt.Sprintf("part 2: area %d", area2)),
+	}}
+	candidates.Paths = append(candidates.Paths, best.Paths[2])
+	return []viz.Figure{
+		{Name: "best", Scene: best},
+		{Name: "candidates", Scene: candidates},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package day09
+
+// segTree is a persistent segment tree over a range of columns, which assigns
+// a value to a span of columns and finds the first column of a span with a
+// large enough value. Nodes are never changed: an assignment returns the root
+// of a new version, which shares the untouched nodes with the previous one.
+type segTree struct {
+	width int
+	nodes []segNode
+}
+
+type segNode struct {
+	left, right int32 // Children, unless uniform
+	max         int32
+	uniform     bool // Every column has the value max
+}
+
+// newSegTree returns a tree whose version 0 has the value v in every column.
+func newSegTree(width int, v int32) segTree {
+	return segTree{width: width, nodes: []segNode{{max: v, uniform: true}}}
+}
+
+func (t *segTree) add(n segNode) int32 {
+	t.nodes = append(t.nodes, n)
+	return int32(len(t.nodes) - 1)
+}
+
+// assign returns the root of a new version of the tree, where the columns of
+// the span have the value v.
+func (t *segTree) assign(root int32, s span, v int32) int32 {
+	return t.assignIn(root, 0, t.width-1, s, v)
+}
+
+func (t *segTree) assignIn(n int32, lo, hi int, s span, v int32) int32 {
+	if s.to < lo || hi < s.from {
+		return n
+	}
+	if s.from <= lo && hi <= s.to {
+		return t.add(segNode{max: v, uniform: true})
+	}
+	left, right := t.nodes[n].left, t.nodes[n].right
+	if t.nodes[n].uniform {
+		// Both children are the same uniform node.
+		left = t.add(segNode{max: t.nodes[n].max, uniform: true})
+		right = left
+	}
+	mid := (lo + hi) / 2
+	left = t.assignIn(left, lo, mid, s, v)
+	right = t.assignIn(right, mid+1, hi, s, v)
+	return t.add(segNode{left: left, right: right, max: max(t.nodes[left].max, t.nodes[right].max)})
+}
+
+// first returns the first column of the span with a value of at least v, and
+// its value, or -1 if there is none.
+func (t *segTree) first(root int32, s span, v int32) (int, int32) {
+	return t.firstIn(root, 0, t.width-1, s, v)
+}
+
+func (t *segTree) firstIn(n int32, lo, hi int, s span, v int32) (int, int32) {
+	if s.to < lo || hi < s.from || t.nodes[n].max < v {
+		return -1, 0
+	}
+	if t.nodes[n].uniform {
+		return max(lo, s.from), t.nodes[n].max
+	}
+	mid := (lo + hi) / 2
+	if c, found := t.firstIn(t.nodes[n].left, lo, mid, s, v); c != -1 {
+		return c, found
+	}
+	return t.firstIn(t.nodes[n].right, mid+1, hi, s, v)
+}
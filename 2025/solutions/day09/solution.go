@@ -5,15 +5,16 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"slices"
 	"strconv"
 
 	"github.com/kanna5/advent_of_code/2025/lib"
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
-// XXX: Despite yielding a correct answer for the given input, not all possible
-// cases have been considered.
+// Part 2: The floor is compressed around the red tiles, and swept row by row
+// into a persistent segment tree of the last row outside the polygon in each
+// column, to check each rectangle in O(log n).
 
 type sol struct {
 	input io.Reader
@@ -25,42 +26,26 @@ func (s *sol) SolvePart1() (string, error) {
 		return "", err
 	}
 
-	maxArea := 0
-	for i := range len(coords) - 1 {
-		for j := i + 1; j < len(coords); j++ {
-			maxArea = max(maxArea, coords[i].Area(&coords[j]))
-		}
-	}
+	_, _, maxArea := largestRect(coords, func(i, j int) bool { return true })
 	return strconv.FormatInt(int64(maxArea), 10), nil
 }
 
-func toEdges(points []Coord) ([]*Line, error) {
-	edges := make([]*Line, 0, len(points))
-	var last *Line
-	turns := 0
-	for i := range points {
-		line := points[i].LineWith(&points[(i+1)%len(points)])
-		if last != nil {
-			switch {
-			case last.Dir.TurnRight() == line.Dir:
-				turns++
-			case last.Dir.TurnLeft() == line.Dir:
-				turns--
-			default:
-				return nil, fmt.Errorf("invalid input: expected turns")
+// largestRect finds the largest rectangle with opposite corners on two of the
+// coordinates, given by their indices to accept. Only rectangles larger than
+// the best so far are offered to accept.
+func largestRect(coords []Coord, accept func(i, j int) bool) (Coord, Coord, int) {
+	var bestA, bestB Coord
+	maxArea := 0
+	for i := range len(coords) - 1 {
+		for j := i + 1; j < len(coords); j++ {
+			area := coords[i].Area(&coords[j])
+			if area <= maxArea || !accept(i, j) {
+				continue
 			}
-		}
-		last = line
-		edges = append(edges, line)
-	}
-	// Make sure coordinates are connected clockwise
-	if turns < 0 {
-		slices.Reverse(edges)
-		for i := range edges {
-			edges[i] = edges[i].Flip()
+			bestA, bestB, maxArea = coords[i], coords[j], area
 		}
 	}
-	return edges, nil
+	return bestA, bestB, maxArea
 }
 
 func toRect(a, b Coord) []Coord {
@@ -70,48 +55,17 @@ func toRect(a, b Coord) []Coord {
 	return []Coord{a, {a.X, b.Y}, b, {b.X, a.Y}}
 }
 
-func isRectInside(a, b Coord, edges []*Line) bool {
-	recP := toRect(a, b)
-	rectE, err := toEdges(recP)
-	if err != nil {
-		return false // just skip the case where it's a thin line
-	}
-
-	for _, e := range edges {
-		for _, rE := range rectE {
-			if e.Overlays(rE) && e.Dir != rE.Dir {
-				return false
-			}
-			if e.Cuts(rE) {
-				return false
-			}
-		}
-	}
-	return true
-}
-
 func (s *sol) SolvePart2() (string, error) {
 	coords, err := readInput(s.input)
 	if err != nil {
 		return "", err
 	}
-	edges, err := toEdges(coords)
+	tiles, err := newTiles(coords)
 	if err != nil {
 		return "", err
 	}
 
-	maxArea := 0
-	for i := range len(coords) - 1 {
-		for j := i + 1; j < len(coords); j++ {
-			if !isRectInside(coords[i], coords[j], edges) {
-				continue
-			}
-			newMA := max(maxArea, coords[i].Area(&coords[j]))
-			if newMA != maxArea {
-				maxArea = newMA
-			}
-		}
-	}
+	_, _, maxArea := largestRect(coords, tiles.Inside)
 	return strconv.FormatInt(int64(maxArea), 10), nil
 }
 
@@ -141,5 +95,8 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[9] = &sol{}
+	registry.Register(solutions.Year, 9, &sol{}, registry.Meta{
+		Title: "Movie Theater",
+		Tags:  []registry.Tag{registry.Geometry},
+	})
 }
@@ -0,0 +1,62 @@
+package day09
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+const example = `7,1
+11,1
+11,7
+9,7
+9,5
+2,5
+2,3
+7,3
+`
+
+func TestSolution(t *testing.T) {
+	for _, tt := range []struct {
+		part  int
+		solve func(*sol) (string, error)
+		want  string
+	}{
+		{1, (*sol).SolvePart1, "50"},
+		{2, (*sol).SolvePart2, "24"},
+	} {
+		s := &sol{}
+		s.WithInput(strings.NewReader(example))
+		got, err := tt.solve(s)
+		if err != nil {
+			t.Fatalf("part %d: %v", tt.part, err)
+		}
+		if got != tt.want {
+			t.Errorf("part %d: got %q, expected %q", tt.part, got, tt.want)
+		}
+	}
+}
+
+func TestVisualize(t *testing.T) {
+	s := &sol{}
+	s.WithInput(strings.NewReader(example))
+	figs, err := s.Visualize(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := map[string]color.Color{}
+	for _, p := range figs[1].Scene.(*viz.Polylines).Paths {
+		titles[p.Title] = p.Stroke
+	}
+	for title, stroke := range map[string]color.Color{
+		"(7,1)-(11,7): edge cuts through, tile (8,7) is outside":                      viz.Red,
+		"(7,1)-(9,7): edge overlays in the opposite direction, tile (8,7) is outside": viz.Orange,
+		"part 2: area 24": viz.Green,
+	} {
+		if got, ok := titles[title]; !ok || got != stroke {
+			t.Errorf("no candidate %q drawn in %v", title, stroke)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package day09
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Tiles tells which tiles are red or green, i.e. on or inside the polygon. The
+// floor is compressed around the red tiles: there is a column for the x of
+// each red tile, and one for each gap between them, and the same for rows. The
+// edges of the polygon are on the columns and rows of red tiles, so all tiles
+// of a compressed cell have the same color.
+//
+// The rows are swept from the top, keeping for each column the last row where
+// its cell is outside the polygon. A rectangle has a tile outside if one of its
+// columns, as of its bottom row, has it no higher than its top row. The cells
+// of a column only change from outside to not, or back, next to the horizontal
+// edges, so the sweep keeps every row as a version of a persistent segment
+// tree, in O(n log n) memory for n red tiles, and checks a rectangle in
+// O(log n).
+type Tiles struct {
+	xs, ys     []int // First tile of each column and row
+	cols, rows []int // Column and row of each red tile
+
+	lastOutside segTree
+	versions    []int32 // Root of lastOutside after each row
+	reach       []span  // Columns around each red tile without a cell outside in its row
+}
+
+// compress returns the first tile of each column or row, for the given
+// coordinates of red tiles.
+func compress(vals []int) []int {
+	vals = slices.Clone(vals)
+	slices.Sort(vals)
+	vals = slices.Compact(vals)
+	ret := make([]int, 0, len(vals)*2)
+	for i, v := range vals {
+		ret = append(ret, v)
+		if i+1 < len(vals) && vals[i+1] > v+1 {
+			ret = append(ret, v+1)
+		}
+	}
+	return ret
+}
+
+// span is a range of columns, or rows, both ends included.
+type span struct{ from, to int }
+
+type vEdge struct {
+	col      int
+	top, bot int // Rows
+}
+
+// Value of lastOutside for the columns whose cell is outside in the current
+// row, or which have never been.
+const (
+	outsideNow   = math.MaxInt32
+	neverOutside = -1
+)
+
+func newTiles(coords []Coord) (*Tiles, error) {
+	xs, ys := make([]int, len(coords)), make([]int, len(coords))
+	for i, c := range coords {
+		xs[i], ys[i] = c.X, c.Y
+	}
+	t := &Tiles{xs: compress(xs), ys: compress(ys)}
+	t.cols, t.rows = make([]int, len(coords)), make([]int, len(coords))
+	byRow := make([][]int, len(t.ys))
+	for i, c := range coords {
+		t.cols[i], t.rows[i] = t.col(c.X), t.row(c.Y)
+		byRow[t.rows[i]] = append(byRow[t.rows[i]], i)
+	}
+
+	vEdges := []vEdge{}
+	hEdges := make([][]span, len(t.ys)) // Horizontal edges in each row
+	for i, a := range coords {
+		b := coords[(i+1)%len(coords)]
+		c1, c2 := t.col(min(a.X, b.X)), t.col(max(a.X, b.X))
+		r1, r2 := t.row(min(a.Y, b.Y)), t.row(max(a.Y, b.Y))
+		switch {
+		case a.Y == b.Y:
+			hEdges[r1] = append(hEdges[r1], span{c1, c2})
+		case a.X == b.X:
+			vEdges = append(vEdges, vEdge{c1, r1, r2})
+		default:
+			return nil, fmt.Errorf("invalid input: %v and %v are not on a line", a, b)
+		}
+	}
+	slices.SortFunc(vEdges, func(a, b vEdge) int { return cmp.Compare(a.top, b.top) })
+
+	t.lastOutside = newSegTree(len(t.xs), neverOutside)
+	t.reach = make([]span, len(coords))
+	root := int32(0)
+	var active []vEdge
+	var prev []span
+	for r := range t.ys {
+		active = slices.DeleteFunc(active, func(e vEdge) bool { return e.bot < r })
+		for len(vEdges) > 0 && vEdges[0].top == r {
+			active = append(active, vEdges[0])
+			vEdges = vEdges[1:]
+		}
+		cur := outsideCells(len(t.xs), r, active, hEdges[r])
+		for _, s := range subtract(cur, prev) {
+			root = t.lastOutside.assign(root, s, outsideNow)
+		}
+		for _, s := range subtract(prev, cur) {
+			root = t.lastOutside.assign(root, s, int32(r-1))
+		}
+		t.versions = append(t.versions, root)
+		prev = cur
+
+		for _, i := range byRow[r] {
+			k, _ := slices.BinarySearchFunc(cur, t.cols[i], func(s span, c int) int { return cmp.Compare(s.from, c) })
+			t.reach[i] = span{0, len(t.xs) - 1}
+			if k > 0 {
+				t.reach[i].from = cur[k-1].to + 1
+			}
+			if k < len(cur) {
+				t.reach[i].to = cur[k].from - 1
+			}
+		}
+	}
+	return t, nil
+}
+
+// outsideCells returns the columns whose cell is outside the polygon in row r,
+// given the vertical edges on the row and the horizontal edges in it.
+func outsideCells(width, r int, active []vEdge, hEdges []span) []span {
+	// A tile off the edges has the color of the point half a tile up and left
+	// of it, which is inside if an odd number of vertical edges cross its row
+	// on the left: those with top < r <= bot.
+	type onEdge struct {
+		span
+		crossing bool
+	}
+	edges := make([]onEdge, 0, len(active)+len(hEdges))
+	for _, e := range active {
+		edges = append(edges, onEdge{span{e.col, e.col}, e.top < r})
+	}
+	for _, s := range hEdges {
+		edges = append(edges, onEdge{s, false})
+	}
+	slices.SortFunc(edges, func(a, b onEdge) int { return cmp.Compare(a.from, b.from) })
+
+	ret := []span{}
+	next, inside := 0, false // First column after the edges so far
+	for _, e := range edges {
+		if e.from > next && !inside {
+			ret = append(ret, span{next, e.from - 1})
+		}
+		next = max(next, e.to+1)
+		inside = inside != e.crossing
+	}
+	if next < width {
+		ret = append(ret, span{next, width - 1})
+	}
+	return ret
+}
+
+// subtract returns the columns in a but not in b, both sorted.
+func subtract(a, b []span) []span {
+	ret := []span{}
+	for _, s := range a {
+		for len(b) > 0 && b[0].to < s.from {
+			b = b[1:]
+		}
+		for _, o := range b {
+			if o.from > s.to {
+				break
+			}
+			if o.from > s.from {
+				ret = append(ret, span{s.from, o.from - 1})
+			}
+			s.from = o.to + 1
+		}
+		if s.from <= s.to {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+func (t *Tiles) col(x int) int {
+	c, _ := slices.BinarySearch(t.xs, x)
+	return c
+}
+
+func (t *Tiles) row(y int) int {
+	r, _ := slices.BinarySearch(t.ys, y)
+	return r
+}
+
+// Outside returns a tile outside the polygon in the rectangle with opposite
+// corners on the red tiles i and j, if there is any.
+func (t *Tiles) Outside(i, j int) (Coord, bool) {
+	c1, c2 := min(t.cols[i], t.cols[j]), max(t.cols[i], t.cols[j])
+	r1, r2 := min(t.rows[i], t.rows[j]), max(t.rows[i], t.rows[j])
+	// Most rectangles have a tile outside on the rows of their corners.
+	for _, k := range [...]int{i, j} {
+		switch reach := t.reach[k]; {
+		case c1 < reach.from:
+			return Coord{t.xs[reach.from-1], t.ys[t.rows[k]]}, true
+		case c2 > reach.to:
+			return Coord{t.xs[reach.to+1], t.ys[t.rows[k]]}, true
+		}
+	}
+	c, last := t.lastOutside.first(t.versions[r2], span{c1, c2}, int32(r1))
+	if c == -1 {
+		return Coord{}, false
+	}
+	if last == outsideNow {
+		last = int32(r2)
+	}
+	return Coord{t.xs[c], t.ys[last]}, true
+}
+
+// Inside tells whether every tile of the rectangle with opposite corners on
+// the red tiles i and j is red or green.
+func (t *Tiles) Inside(i, j int) bool {
+	_, outside := t.Outside(i, j)
+	return !outside
+}
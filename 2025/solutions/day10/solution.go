@@ -10,6 +10,7 @@ import (
 
 	"github.com/kanna5/advent_of_code/2025/lib"
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -288,5 +289,8 @@ func readInput(input io.Reader) ([]*Machine, error) {
 }
 
 func init() {
-	solutions.Days[10] = &sol{}
+	registry.Register(solutions.Year, 10, &sol{}, registry.Meta{
+		Title: "Factory",
+		Tags:  []registry.Tag{registry.Math},
+	})
 }
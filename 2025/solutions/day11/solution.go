@@ -1,89 +1,179 @@
 // Solution for https://adventofcode.com/2025/day/11
 package day11
 
+// Both parts count the paths between two devices which visit some required
+// devices in any order. The devices on a path are counted once for each set of
+// required ones already visited, so any number of them can be required.
+// Options change the query of both parts, e.g. -o from=svr -o to=out -o via=fft,dac.
+
 import (
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/kanna5/advent_of_code/2025/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
-// Assume there are no loops.
-
 type sol struct {
 	input io.Reader
+	opts  registry.Options
+}
+
+type Query struct {
+	From, To string
+	Via      []string
 }
 
-func findPaths(conn [][]int, current, dest int, cache []int) int {
-	if cache == nil {
-		cache = make([]int, len(conn))
-		for i := range cache {
-			cache[i] = -1
+var defaultQueries = [...]Query{
+	1: {From: "you", To: "out"},
+	2: {From: "svr", To: "out", Via: []string{"fft", "dac"}},
+}
+
+func (q Query) With(opts registry.Options) (Query, error) {
+	if err := opts.Check("from", "to", "via"); err != nil {
+		return q, err
+	}
+	if v, ok := opts["from"]; ok {
+		q.From = v
+	}
+	if v, ok := opts["to"]; ok {
+		q.To = v
+	}
+	if v, ok := opts["via"]; ok {
+		q.Via = nil
+		if len(v) != 0 {
+			q.Via = strings.Split(v, ",")
 		}
-		cache[dest] = 1
 	}
-	if cache[current] != -1 {
-		return cache[current]
+	return q, nil
+}
+
+type pathCounter struct {
+	rack  *Rack
+	to    int
+	bits  map[int]int // Bit of each required device
+	all   int
+	memo  map[[2]int]int // Paths from (device, required devices visited)
+	stack []int          // Devices being counted, to report loops
+}
+
+func (c *pathCounter) count(node, visited int) (int, error) {
+	visited |= c.bits[node]
+	if node == c.to {
+		if visited == c.all {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	st := [2]int{node, visited}
+	if n, ok := c.memo[st]; ok {
+		if n == -1 {
+			i := slices.Index(c.stack, node)
+			names := []string{}
+			for _, n := range c.stack[i:] {
+				names = append(names, c.rack.Names[n])
+			}
+			names = append(names, c.rack.Names[node])
+			return 0, fmt.Errorf("the devices loop: %s", strings.Join(names, " -> "))
+		}
+		return n, nil
 	}
 
+	c.memo[st] = -1 // Being counted
+	c.stack = append(c.stack, node)
 	sum := 0
-	for _, next := range conn[current] {
-		sum += findPaths(conn, next, dest, cache)
+	for _, next := range c.rack.Connections[node] {
+		n, err := c.count(next, visited)
+		if err != nil {
+			return 0, err
+		}
+		sum += n
 	}
-	cache[current] = sum
-	return sum
+	c.stack = c.stack[:len(c.stack)-1]
+	c.memo[st] = sum
+	return sum, nil
 }
 
-func (s *sol) SolvePart1() (string, error) {
-	rack, err := readInput(s.input)
+// countPaths returns the number of paths of the query. An error is returned if
+// a device is unknown, or if the devices loop.
+func countPaths(rack *Rack, q Query) (int, error) {
+	id := func(name string) (int, error) {
+		n, ok := rack.Nodes[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown device %q", name)
+		}
+		return n, nil
+	}
+	from, err := id(q.From)
 	if err != nil {
-		return "", err
+		return 0, err
 	}
-
-	paths := findPaths(rack.Connections, rack.You, rack.Out, nil)
-	return strconv.FormatInt(int64(paths), 10), nil
+	to, err := id(q.To)
+	if err != nil {
+		return 0, err
+	}
+	c := &pathCounter{rack: rack, to: to, bits: map[int]int{}, memo: map[[2]int]int{}}
+	for _, name := range q.Via {
+		n, err := id(name)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := c.bits[n]; !ok {
+			c.bits[n] = 1 << len(c.bits)
+		}
+	}
+	if len(c.bits) > 32 {
+		return 0, fmt.Errorf("too many required devices: %d", len(c.bits))
+	}
+	c.all = 1<<len(c.bits) - 1
+	return c.count(from, 0)
 }
 
-func (s *sol) SolvePart2() (string, error) {
+func (s *sol) solve(part int) (string, error) {
+	q, err := defaultQueries[part].With(s.opts)
+	if err != nil {
+		return "", err
+	}
 	rack, err := readInput(s.input)
 	if err != nil {
 		return "", err
 	}
 
-	svr, ok1 := rack.Nodes["svr"]
-	fft, ok2 := rack.Nodes["fft"]
-	dac, ok3 := rack.Nodes["dac"]
-	if !ok1 || !ok2 || !ok3 {
-		return "", fmt.Errorf("no enough nodes. required: svr, fft, dac")
+	paths, err := countPaths(rack, q)
+	if err != nil {
+		return "", err
 	}
+	return strconv.FormatInt(int64(paths), 10), nil
+}
 
-	fft2dac := findPaths(rack.Connections, fft, dac, nil)
-	dac2fft := findPaths(rack.Connections, dac, fft, nil)
-	var paths int
-
-	switch {
-	case fft2dac > 0:
-		a := findPaths(rack.Connections, svr, fft, nil)
-		b := findPaths(rack.Connections, dac, rack.Out, nil)
-		paths = a * fft2dac * b
-
-	case dac2fft > 0:
-		a := findPaths(rack.Connections, svr, dac, nil)
-		b := findPaths(rack.Connections, fft, rack.Out, nil)
-		paths = a * dac2fft * b
-
-	default:
-		return "", fmt.Errorf("impossible: no path between fft and dac")
-	}
+func (s *sol) SolvePart1() (string, error) {
+	return s.solve(1)
+}
 
-	return strconv.FormatInt(int64(paths), 10), nil
+func (s *sol) SolvePart2() (string, error) {
+	return s.solve(2)
 }
 
 func (s *sol) WithInput(i io.Reader) {
 	s.input = i
 }
 
+// SetOptions changes the query of both parts: from and to are devices, and via
+// is a comma-separated list of devices to visit.
+func (s *sol) SetOptions(opts registry.Options) error {
+	if _, err := (Query{}).With(opts); err != nil {
+		return err
+	}
+	s.opts = opts
+	return nil
+}
+
 func init() {
-	solutions.Days[11] = &sol{}
+	registry.Register(solutions.Year, 11, &sol{}, registry.Meta{
+		Title: "Reactor",
+		Tags:  []registry.Tag{registry.Graph, registry.DP},
+	})
 }
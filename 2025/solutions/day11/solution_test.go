@@ -0,0 +1,81 @@
+package day11
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kanna5/advent_of_code/common/registry"
+)
+
+const example1 = `aaa: you hhh
+you: bbb ccc
+bbb: ddd eee
+ccc: ddd eee fff
+ddd: ggg
+eee: out
+fff: out
+ggg: out
+hhh: ccc fff iii
+iii: out
+`
+
+const example2 = `svr: aaa bbb
+aaa: fft
+fft: ccc
+bbb: tty
+tty: ccc
+ccc: ddd eee
+ddd: hub
+hub: fff
+eee: dac
+dac: fff
+fff: ggg hhh
+ggg: out
+hhh: out
+`
+
+func TestSolution(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  registry.Options
+		input string
+		part  int
+		want  string
+		err   string
+	}{
+		{name: "example1", input: example1, part: 1, want: "5"},
+		{name: "example2", input: example2, part: 2, want: "2"},
+		{name: "no via", opts: registry.Options{"via": ""}, input: example2, part: 2, want: "8"},
+		{name: "via one", opts: registry.Options{"via": "hub"}, input: example2, part: 2, want: "4"},
+		{name: "via three", opts: registry.Options{"via": "fft,dac,hub"}, input: example2, part: 2, want: "0"},
+		{name: "from", opts: registry.Options{"from": "ccc"}, input: example1, part: 1, want: "3"},
+		// The loop is not on any path from you.
+		{name: "unreachable loop", input: example1 + "xxx: yyy\nyyy: xxx out\n", part: 1, want: "5"},
+		{name: "loop", input: "you: aaa\naaa: bbb out\nbbb: ccc\nccc: aaa\n", part: 1, err: "the devices loop: aaa -> bbb -> ccc -> aaa"},
+		{name: "self loop", input: "you: you out\n", part: 1, err: "the devices loop: you -> you"},
+		{name: "unknown device", input: example1, part: 2, err: `unknown device "svr"`},
+		{name: "unknown via", opts: registry.Options{"via": "zzz"}, input: example1, part: 1, err: `unknown device "zzz"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sol{}
+			if err := s.SetOptions(tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			s.WithInput(strings.NewReader(tt.input))
+			got, err := s.solve(tt.part)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, expected %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
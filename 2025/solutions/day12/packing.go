@@ -0,0 +1,563 @@
+package day12
+
+import (
+	"cmp"
+	"errors"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// Packing presents is solved as an exact cover problem with Dancing Links.
+// ref: https://en.wikipedia.org/wiki/Dancing_Links
+//
+// There is a primary column for each shape, which must be covered as many times
+// as there are presents of the shape (an extension of exact cover with
+// multiplicities), and a secondary column for each cell of the region, which
+// can be covered at most once as cells may be left empty. Each row is a
+// placement of a shape in one of its orientations.
+//
+// The search branches on the cells in reading order: the first cell not
+// covered yet is either covered by one of its rows, or left empty, which
+// covers its column without a row. As the cells before it are all covered, its
+// rows all start there.
+//
+// The cells which can't be covered, i.e. free cells whose column has no row
+// left, are the slack of the region, as the tiles of the presents must fit in
+// the others. A choice wastes the cell if it is left empty, and the cells next
+// to it it leaves without a row. Choices wasting less are tried first, and
+// those wasting more than the slack not at all. The search also gives up on a
+// state when the groups of connected free cells can't hold the remaining
+// presents: each holds at most the largest sum of shape sizes that fits in it.
+//
+// Covering a row moves many nodes, so the waste of the choices is measured on
+// a copy of the covered cells as bit sets, which checks where the presents left
+// fit in a row of the region with a few bit operations. Regions with both sides
+// over 64 cells are measured by covering the rows.
+//
+// The state of the search is only the cell, the covered cells of the rows a
+// present placed there can reach, and the presents left. States leading
+// nowhere are remembered.
+//
+// A region with room to spare has many packings, but the search may be stuck
+// for long behind a bad early choice. So it restarts now and then with the
+// orientations tried in another order, following the Luby sequence of the
+// states to visit. The dead states found before are still dead.
+// ref: https://doi.org/10.1016/0020-0190(93)90029-9
+
+var (
+	errTimeout = errors.New("timed out")
+	errRestart = errors.New("restart")
+)
+
+const (
+	maxMemo     = 1 << 22 // Dead states remembered at most, to bound the memory used
+	restartUnit = 64      // States visited between restarts, times the Luby sequence
+	maxBitsSide = 64      // Cells in a row of the region to measure the waste with bit sets
+)
+
+// kind is a shape in one of its orientations.
+type kind struct {
+	shape int // Column of the shape
+	w, h  int
+	tiles []Cell
+}
+
+// placement is the row of a present put somewhere in the region.
+type placement struct {
+	shape int      // Column of the shape
+	kind  int      // Shape and orientation
+	y     int      // First row covered
+	rows  []uint64 // Cells covered in each row from y, if measured with bit sets
+}
+
+type packer struct {
+	// Links of the nodes. Node 0 is the root, followed by the column headers,
+	// then the nodes of the rows.
+	left, right, up, down, col []int
+	rowOf                      []int // Placement of each node, -1 for headers
+
+	size    []int // Number of rows in each column
+	need    []int // Remaining number of times to cover each shape column
+	vol     []int // Number of tiles of each shape column
+	nShapes int
+
+	placements []placement
+	kinds      []kind // Shapes with presents to place, in all orientations
+	rank       []int  // Order in which the kinds are tried
+	w, h       int
+	reach      int      // Rows a placement covers at most
+	covered    []bool   // Cells whose column is covered
+	bits       []uint64 // Covered cells in each row, if measured with bit sets
+	fill       []int    // Largest sum of shape sizes up to each number of cells
+
+	free, remaining, dead int // Cells and tiles to track the slack
+
+	failed map[string]bool // States which lead nowhere
+	key    []byte
+	seen   []bool // Buffers of fillable and deadCells
+	stack  []int
+	fitAt  []uint64
+
+	nodes    int // States visited
+	limit    int // States to visit before restarting
+	deadline time.Time
+}
+
+func (p *packer) addNode(c, row int) int {
+	n := len(p.col)
+	p.left = append(p.left, n)
+	p.right = append(p.right, n)
+	p.up = append(p.up, p.up[c])
+	p.down = append(p.down, c)
+	p.col = append(p.col, c)
+	p.rowOf = append(p.rowOf, row)
+	p.down[p.up[c]] = n
+	p.up[c] = n
+	p.size[c]++
+	return n
+}
+
+func (p *packer) addRow(pl placement, cells []int) {
+	row := len(p.placements)
+	p.placements = append(p.placements, pl)
+	first := p.addNode(pl.shape, row)
+	for _, cell := range cells {
+		n := p.addNode(p.cellCol(cell), row)
+		p.left[n], p.right[n] = p.left[first], first
+		p.right[p.left[first]] = n
+		p.left[first] = n
+	}
+}
+
+func newPacker(shapes []*Shape, r *Region) *packer {
+	// Shapes come in all orientations, so the region is turned to have fewer
+	// cells per row, i.e. fewer states.
+	w, h := min(r.w, r.h), max(r.w, r.h)
+	nShapes, nCells := len(shapes), w*h
+	nCols := nShapes + nCells
+	p := &packer{
+		size:    make([]int, 1+nCols),
+		need:    make([]int, 1+nShapes),
+		vol:     make([]int, 1+nShapes),
+		nShapes: nShapes,
+		w:       w,
+		h:       h,
+		covered: make([]bool, nCells),
+		free:    nCells,
+		failed:  map[string]bool{},
+		seen:    make([]bool, nCells),
+	}
+	if w <= maxBitsSide {
+		p.bits = make([]uint64, h)
+	}
+	for c := range 1 + nCols {
+		p.left = append(p.left, c)
+		p.right = append(p.right, c)
+		p.up = append(p.up, c)
+		p.down = append(p.down, c)
+		p.col = append(p.col, c)
+		p.rowOf = append(p.rowOf, -1)
+	}
+	// Only the shape columns with presents to place are linked to the root.
+	for i, s := range shapes {
+		c := 1 + i
+		p.vol[c] = s.Vol()
+		if i >= len(r.presents) || r.presents[i] == 0 {
+			continue
+		}
+		p.need[c] = r.presents[i]
+		p.remaining += p.need[c] * p.vol[c]
+		p.left[c], p.right[c] = p.left[0], 0
+		p.right[p.left[0]] = c
+		p.left[0] = c
+	}
+
+	for i, s := range shapes {
+		c := 1 + i
+		if p.need[c] == 0 {
+			continue
+		}
+		for _, o := range s.Orientations() {
+			ow, oh := 0, 0
+			for _, t := range o {
+				ow, oh = max(ow, t.x+1), max(oh, t.y+1)
+			}
+			p.kinds = append(p.kinds, kind{shape: c, w: ow, h: oh, tiles: o})
+			p.reach = max(p.reach, oh)
+			for y := 0; y+oh <= h; y++ {
+				for x := 0; x+ow <= w; x++ {
+					pl := placement{shape: c, kind: len(p.kinds) - 1, y: y}
+					cells := make([]int, len(o))
+					for j, t := range o {
+						cells[j] = (y+t.y)*w + x + t.x
+					}
+					if p.bits != nil {
+						pl.rows = make([]uint64, oh)
+						for _, t := range o {
+							pl.rows[t.y] |= 1 << (x + t.x)
+						}
+					}
+					p.addRow(pl, cells)
+				}
+			}
+		}
+	}
+	p.rank = make([]int, len(p.kinds))
+	for i := range p.rank {
+		p.rank[i] = i
+	}
+	p.fitAt = make([]uint64, p.reach)
+	for cell := range nCells {
+		if p.size[p.cellCol(cell)] == 0 {
+			p.dead++
+		}
+	}
+
+	sums := make([]bool, nCells+1)
+	sums[0] = true
+	p.fill = make([]int, nCells+1)
+	for n := 1; n <= nCells; n++ {
+		p.fill[n] = p.fill[n-1]
+		for c := 1; c <= nShapes; c++ {
+			if v := p.vol[c]; p.need[c] > 0 && v <= n && sums[n-v] {
+				sums[n], p.fill[n] = true, n
+			}
+		}
+	}
+	return p
+}
+
+func (p *packer) cellCol(cell int) int {
+	return 1 + p.nShapes + cell
+}
+
+func (p *packer) isCell(c int) bool {
+	return c > p.nShapes
+}
+
+// unlinkRow removes the nodes of a row from their columns, except n.
+func (p *packer) unlinkRow(n int) {
+	for j := p.right[n]; j != n; j = p.right[j] {
+		p.down[p.up[j]] = p.down[j]
+		p.up[p.down[j]] = p.up[j]
+		c := p.col[j]
+		p.size[c]--
+		if p.isCell(c) && p.size[c] == 0 {
+			p.dead++
+		}
+	}
+}
+
+func (p *packer) relinkRow(n int) {
+	for j := p.left[n]; j != n; j = p.left[j] {
+		c := p.col[j]
+		if p.isCell(c) && p.size[c] == 0 {
+			p.dead--
+		}
+		p.size[c]++
+		p.down[p.up[j]] = j
+		p.up[p.down[j]] = j
+	}
+}
+
+// setCovered marks a cell as covered or not.
+func (p *packer) setCovered(cell int, covered bool) {
+	p.covered[cell] = covered
+	if p.bits != nil {
+		p.bits[cell/p.w] ^= 1 << (cell % p.w)
+	}
+}
+
+// cover removes a column and all rows intersecting it. Covered cells are no
+// longer free, so they are not counted as dead either.
+func (p *packer) cover(c int) {
+	p.right[p.left[c]] = p.right[c]
+	p.left[p.right[c]] = p.left[c]
+	if p.isCell(c) {
+		p.setCovered(c-1-p.nShapes, true)
+		p.free--
+		if p.size[c] == 0 {
+			p.dead--
+		}
+	}
+	for i := p.down[c]; i != c; i = p.down[i] {
+		p.unlinkRow(i)
+	}
+}
+
+func (p *packer) uncover(c int) {
+	for i := p.up[c]; i != c; i = p.up[i] {
+		p.relinkRow(i)
+	}
+	if p.isCell(c) {
+		p.setCovered(c-1-p.nShapes, false)
+		p.free++
+		if p.size[c] == 0 {
+			p.dead++
+		}
+	}
+	p.right[p.left[c]] = c
+	p.left[p.right[c]] = c
+}
+
+// place covers the columns of the row of node n, but the column of n which is
+// covered already.
+func (p *packer) place(n int) {
+	for j := p.right[n]; j != n; j = p.right[j] {
+		c := p.col[j]
+		if p.isCell(c) {
+			p.cover(c)
+			continue
+		}
+		p.need[c]--
+		p.remaining -= p.vol[c]
+		if p.need[c] == 0 {
+			p.cover(c)
+		}
+	}
+}
+
+func (p *packer) unplace(n int) {
+	for j := p.left[n]; j != n; j = p.left[j] {
+		c := p.col[j]
+		if p.isCell(c) {
+			p.uncover(c)
+			continue
+		}
+		if p.need[c] == 0 {
+			p.uncover(c)
+		}
+		p.need[c]++
+		p.remaining += p.vol[c]
+	}
+}
+
+// deadCells counts the free cells from row y which no present left can cover,
+// up to the rows a present placed in row y can reach, with the bit sets. The
+// columns where a present fits are found for all columns at once: those where
+// every tile is on a free cell.
+func (p *packer) deadCells(y int) int {
+	end := min(y+p.reach, p.h)
+	fitAt := p.fitAt[:end-y]
+	clear(fitAt)
+	for _, k := range p.kinds {
+		if p.need[k.shape] == 0 {
+			continue
+		}
+		for top := max(y-k.h+1, 0); top < end && top+k.h <= p.h; top++ {
+			fit := uint64(1)<<(p.w-k.w+1) - 1
+			for _, t := range k.tiles {
+				if fit &= ^p.bits[top+t.y] >> t.x; fit == 0 {
+					break
+				}
+			}
+			if fit == 0 {
+				continue
+			}
+			for _, t := range k.tiles {
+				if r := top + t.y - y; r >= 0 && r < len(fitAt) {
+					fitAt[r] |= fit << t.x
+				}
+			}
+		}
+	}
+	ret := 0
+	for r, c := range fitAt {
+		ret += bits.OnesCount64(^p.bits[y+r] &^ c & (1<<p.w - 1))
+	}
+	return ret
+}
+
+// waste returns the cells wasted by covering the cell with the row of node n,
+// or by leaving it empty if n is -1.
+func (p *packer) waste(cell, n int) int {
+	if p.bits == nil {
+		// Measured by covering the row, with the cell's column covered.
+		if n == -1 {
+			return p.dead + 1
+		}
+		p.place(n)
+		defer p.unplace(n)
+		return p.dead
+	}
+
+	// The cell is covered already.
+	if n == -1 {
+		return p.deadCells(cell/p.w) + 1
+	}
+	pl := &p.placements[p.rowOf[n]]
+	for i, r := range pl.rows {
+		p.bits[pl.y+i] |= r
+	}
+	p.need[pl.shape]--
+	defer func() {
+		for i, r := range pl.rows {
+			p.bits[pl.y+i] &^= r
+		}
+		p.bits[cell/p.w] |= 1 << (cell % p.w)
+		p.need[pl.shape]++
+	}()
+	return p.deadCells(cell / p.w)
+}
+
+// slack returns the free cells which are not dead, minus the tiles of the
+// remaining presents.
+func (p *packer) slack() int {
+	return p.free - p.dead - p.remaining
+}
+
+// fillable returns the most tiles the free cells from the given one can hold.
+func (p *packer) fillable(from int) int {
+	clear(p.seen)
+	ret := 0
+	for start := from; start < len(p.covered); start++ {
+		if p.covered[start] || p.seen[start] {
+			continue
+		}
+		p.seen[start] = true
+		n := 0
+		for p.stack = append(p.stack[:0], start); len(p.stack) > 0; n++ {
+			cell := p.stack[len(p.stack)-1]
+			p.stack = p.stack[:len(p.stack)-1]
+			x, y := cell%p.w, cell/p.w
+			for _, nb := range [...]Cell{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if nb.x < 0 || nb.x >= p.w || nb.y < 0 || nb.y >= p.h {
+					continue
+				}
+				if i := nb.y*p.w + nb.x; !p.covered[i] && !p.seen[i] {
+					p.seen[i] = true
+					p.stack = append(p.stack, i)
+				}
+			}
+		}
+		ret += p.fill[n]
+	}
+	return ret
+}
+
+// stateKey encodes the state of the search at the cell: the covered cells of
+// the rows a present placed there can reach, and the presents left. The cells
+// of the rows below are all free.
+func (p *packer) stateKey(cell int) string {
+	b := append(p.key[:0], byte(cell), byte(cell>>8), byte(cell>>16))
+	end := min((cell/p.w+p.reach)*p.w, len(p.covered))
+	for i := cell; i < end; i += 8 {
+		var bits byte
+		for j := i; j < min(i+8, end); j++ {
+			if p.covered[j] {
+				bits |= 1 << (j - i)
+			}
+		}
+		b = append(b, bits)
+	}
+	for _, n := range p.need[1:] {
+		b = append(b, byte(n), byte(n>>8))
+	}
+	p.key = b
+	return string(b)
+}
+
+// choice is a row covering the cell, as one of its nodes, or -1 to leave the
+// cell empty.
+type choice struct {
+	node, waste, rank int
+}
+
+// search looks for a placement of all remaining presents, from the cell.
+func (p *packer) search(cell int) (bool, error) {
+	if p.remaining == 0 {
+		return true, nil
+	}
+	if p.slack() < 0 {
+		return false, nil
+	}
+	for c := p.right[0]; c != 0; c = p.right[c] {
+		if p.size[c] < p.need[c] {
+			return false, nil
+		}
+	}
+	for p.covered[cell] {
+		cell++
+	}
+	if p.nodes++; p.nodes > p.limit {
+		return false, errRestart
+	}
+	if p.nodes%4096 == 0 && time.Now().After(p.deadline) {
+		return false, errTimeout
+	}
+	key := p.stateKey(cell)
+	if p.failed[key] {
+		return false, nil
+	}
+
+	ok, err := false, error(nil)
+	if p.fillable(cell) >= p.remaining {
+		free := p.free
+		c := p.cellCol(cell)
+		p.cover(c)
+		// Choices wasting fewer cells are tried first, then in the order of
+		// the kinds, leaving the cell empty last.
+		choices := []choice{}
+		for n := p.down[c]; n != c; n = p.down[n] {
+			pl := &p.placements[p.rowOf[n]]
+			choices = append(choices, choice{n, p.waste(cell, n), p.rank[pl.kind]})
+		}
+		choices = append(choices, choice{-1, p.waste(cell, -1), len(p.rank)})
+		slices.SortFunc(choices, func(a, b choice) int {
+			return cmp.Or(a.waste-b.waste, a.rank-b.rank)
+		})
+		for i := 0; i < len(choices) && !ok && err == nil; i++ {
+			if free-choices[i].waste < p.remaining {
+				break
+			}
+			if n := choices[i].node; n != -1 {
+				p.place(n)
+				ok, err = p.search(cell + 1)
+				p.unplace(n)
+			} else {
+				ok, err = p.search(cell + 1)
+			}
+		}
+		p.uncover(c)
+	}
+
+	if !ok && err == nil && len(p.failed) < maxMemo {
+		p.failed[key] = true
+	}
+	return ok, err
+}
+
+// canPack tells whether the presents fit in the region, searching for at most
+// the given time.
+func canPack(shapes []*Shape, r *Region, budget time.Duration) (bool, error) {
+	p := newPacker(shapes, r)
+	p.deadline = time.Now().Add(budget)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for round := 1; ; round++ {
+		p.limit = restartUnit * luby(round)
+		p.nodes = 0
+		ok, err := p.search(0)
+		if !errors.Is(err, errRestart) {
+			return ok, err
+		}
+		p.rank = rng.Perm(len(p.kinds))
+		if time.Now().After(p.deadline) {
+			return false, errTimeout
+		}
+	}
+}
+
+// luby returns the i-th term of the Luby sequence, counted from 1: 1, 1, 2, 1,
+// 1, 2, 4, 1, 1, 2, 1, 1, 2, 4, 8, ...
+func luby(i int) int {
+	for k := 1; ; k++ {
+		if i == 1<<k-1 {
+			return 1 << (k - 1)
+		}
+		if i < 1<<k-1 {
+			return luby(i - (1<<(k-1) - 1))
+		}
+	}
+}
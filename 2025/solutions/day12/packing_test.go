@@ -0,0 +1,82 @@
+package day12
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const exampleShapes = `0:
+###
+##.
+##.
+
+1:
+###
+##.
+.##
+
+2:
+.##
+###
+##.
+
+3:
+##.
+###
+##.
+
+4:
+###
+#..
+###
+
+5:
+###
+.#.
+###
+
+`
+
+// Regions close to the limit of the area of their presents.
+func TestCanPack(t *testing.T) {
+	tests := []struct {
+		region string
+		want   bool
+	}{
+		{"4x4: 0 0 0 0 2 0", true},
+		{"12x5: 1 0 1 0 2 2", true},
+		{"12x5: 1 0 1 0 3 2", false},
+		{"8x8: 2 2 2 1 1 1", false},
+		{"9x9: 2 2 2 2 2 1", true},
+		{"10x10: 2 3 3 2 2 2", false},
+		{"11x11: 2 3 3 3 3 2", true},
+		{"12x12: 3 3 3 3 3 3", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			shapes, regions, err := readInput(strings.NewReader(exampleShapes + tt.region + "\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := canPack(shapes, regions[0], packingBudget)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLuby(t *testing.T) {
+	want := []int{1, 1, 2, 1, 1, 2, 4, 1, 1, 2, 1, 1, 2, 4, 8, 1}
+	got := []int{}
+	for i := range want {
+		got = append(got, luby(i+1))
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, expected %v", got, want)
+	}
+}
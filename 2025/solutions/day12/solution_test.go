@@ -0,0 +1,22 @@
+package day12
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSolvePart1(t *testing.T) {
+	example := exampleShapes + `4x4: 0 0 0 0 2 0
+12x5: 1 0 1 0 2 2
+12x5: 1 0 1 0 3 2
+`
+	s := &sol{}
+	s.WithInput(strings.NewReader(example))
+	got, err := s.SolvePart1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "2" {
+		t.Errorf("got %q, expected %q", got, "2")
+	}
+}
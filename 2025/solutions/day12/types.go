@@ -1,5 +1,11 @@
 package day12
 
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
 type Shape struct {
 	label string
 	w, h  int
@@ -22,3 +28,56 @@ type Region struct {
 	w, h     int
 	presents []int
 }
+
+type Cell struct{ x, y int }
+
+// Orientations returns the distinct rotations and reflections of the shape, as
+// lists of tiles sorted by row then column, with the top-left corner of the
+// bounding box at (0, 0).
+func (s *Shape) Orientations() [][]Cell {
+	tiles := make([]Cell, 0, s.Vol())
+	for y := range s.tiles {
+		for x := range s.tiles[y] {
+			if s.tiles[y][x] {
+				tiles = append(tiles, Cell{x, y})
+			}
+		}
+	}
+
+	ret := [][]Cell{}
+	seen := map[string]bool{}
+	for flip := range 2 {
+		for rot := range 4 {
+			o := make([]Cell, len(tiles))
+			for i, t := range tiles {
+				if flip == 1 {
+					t.x = -t.x
+				}
+				for range rot {
+					t.x, t.y = -t.y, t.x
+				}
+				o[i] = t
+			}
+			normalize(o)
+			if key := fmt.Sprint(o); !seen[key] {
+				seen[key] = true
+				ret = append(ret, o)
+			}
+		}
+	}
+	return ret
+}
+
+func normalize(tiles []Cell) {
+	minX, minY := tiles[0].x, tiles[0].y
+	for _, t := range tiles {
+		minX, minY = min(minX, t.x), min(minY, t.y)
+	}
+	for i := range tiles {
+		tiles[i].x -= minX
+		tiles[i].y -= minY
+	}
+	slices.SortFunc(tiles, func(a, b Cell) int {
+		return cmp.Or(cmp.Compare(a.y, b.y), cmp.Compare(a.x, b.x))
+	})
+}
@@ -1,13 +1,9 @@
 package solutions
 
-import "io"
+import "github.com/kanna5/advent_of_code/common/registry"
 
-type Solver interface {
-	WithInput(i io.Reader)
-	SolvePart1() (string, error)
-	SolvePart2() (string, error)
-}
+const Year = 2025
 
-var (
-	Days [13]Solver // Use index 1 ~ 12
-)
+func init() {
+	registry.RegisterYear(Year, 12)
+}
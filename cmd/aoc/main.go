@@ -0,0 +1,7 @@
+package main
+
+import "github.com/kanna5/advent_of_code/common/cli"
+
+func main() {
+	cli.Main()
+}
@@ -0,0 +1,8 @@
+package main
+
+// Each year registers its solutions on import. Add a line here to link a new
+// year into the command.
+import (
+	_ "github.com/kanna5/advent_of_code/2023/solutions/all"
+	_ "github.com/kanna5/advent_of_code/2025/solutions/all"
+)
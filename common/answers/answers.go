@@ -0,0 +1,62 @@
+// Package answers reads and writes answers.json, which records the expected
+// answers of each day of a year, for both the example and the actual input.
+package answers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Answers of a day. Empty fields are not known yet.
+type Answers struct {
+	Example1 string `json:"example1"`
+	Example2 string `json:"example2"`
+	Part1    string `json:"part1"`
+	Part2    string `json:"part2"`
+}
+
+// File maps days (formatted as "%02d") to their answers.
+type File map[string]*Answers
+
+func key(day int) string {
+	return fmt.Sprintf("%02d", day)
+}
+
+func (f File) Get(day int) *Answers {
+	return f[key(day)]
+}
+
+// Add inserts an empty entry for a day, keeping the existing one if any. It
+// returns whether an entry was added.
+func (f File) Add(day int) bool {
+	if _, ok := f[key(day)]; ok {
+		return false
+	}
+	f[key(day)] = &Answers{}
+	return true
+}
+
+// Load reads an answers file. A missing file is treated as empty.
+func Load(pth string) (File, error) {
+	content, err := os.ReadFile(pth)
+	if os.IsNotExist(err) {
+		return File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f := File{}
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf("invalid answers file %q: %v", pth, err)
+	}
+	return f, nil
+}
+
+func (f File) Save(pth string) error {
+	content, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(pth, append(content, '\n'), 0o644)
+}
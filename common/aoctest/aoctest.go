@@ -0,0 +1,79 @@
+// Package aoctest checks solutions against the answers recorded in
+// answers.json. It is used by the tests generated for each day.
+package aoctest
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/kanna5/advent_of_code/common/answers"
+	"github.com/kanna5/advent_of_code/common/registry"
+	"github.com/kanna5/advent_of_code/common/runner"
+)
+
+// YearDir is the year's module directory, relative to a day's package.
+const YearDir = "../.."
+
+type check struct {
+	name     string
+	part     int
+	input    string
+	expected string
+}
+
+func solve(t *testing.T, solver registry.Solver, part int, input string) string {
+	t.Helper()
+	fd, err := os.Open(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = fd.Close() }()
+
+	solver.WithInput(fd)
+	var result string
+	switch part {
+	case 1:
+		result, err = solver.SolvePart1()
+	case 2:
+		result, err = solver.SolvePart2()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// Run solves both parts of a day with the examples and the actual input. A
+// check is skipped if its answer is not recorded, or its input is missing.
+func Run(t *testing.T, day int, newSolver func() registry.Solver) {
+	t.Helper()
+	ans, err := answers.Load(path.Join(YearDir, "answers.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := ans.Get(day)
+	if a == nil {
+		t.Skipf("no answers recorded for day %d", day)
+	}
+
+	checks := []check{
+		{"example/part1", 1, runner.ExampleFor(YearDir, day, 1), a.Example1},
+		{"example/part2", 2, runner.ExampleFor(YearDir, day, 2), a.Example2},
+		{"input/part1", 1, runner.DefaultInput(YearDir, day), a.Part1},
+		{"input/part2", 2, runner.DefaultInput(YearDir, day), a.Part2},
+	}
+	for _, c := range checks {
+		t.Run(c.name, func(t *testing.T) {
+			if c.expected == "" {
+				t.Skip("answer is not recorded")
+			}
+			if _, err := os.Stat(c.input); err != nil {
+				t.Skipf("input is missing: %v", err)
+			}
+			if got := solve(t, newSolver(), c.part, c.input); got != c.expected {
+				t.Errorf("got %q, expected %q", got, c.expected)
+			}
+		})
+	}
+}
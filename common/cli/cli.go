@@ -0,0 +1,159 @@
+// Package cli is the command line interface shared by the binaries: the aoc
+// command running every year, and the binary of each year.
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/kanna5/advent_of_code/common/registry"
+	"github.com/kanna5/advent_of_code/common/runner"
+)
+
+// command line of a binary. A binary of a single year runs in the year's
+// directory, and takes no <year> argument.
+type command struct {
+	year int // Zero if the year is an argument
+	opts runner.Options
+}
+
+// Main runs the command line of a binary. The binary of a year passes its year,
+// and runs in the year's directory. Otherwise, the year is the first argument
+// and the working directory has a directory for each registered year.
+func Main(years ...int) {
+	c := &command{}
+	if len(years) == 1 {
+		c.year = years[0]
+	}
+	flag.Usage = c.usage
+	c.opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	args := flag.Args()
+
+	if len(args) >= 1 {
+		switch args[0] {
+		case "list":
+			c.list(args[1:])
+			return
+		case "new":
+			c.newDay(args[1:])
+			return
+		case "watch":
+			c.watchDay(args[1:])
+			return
+		}
+	}
+	c.run(args)
+}
+
+func (c *command) usage() {
+	year, years := "<year> ", " [year...]"
+	if c.year != 0 {
+		year, years = "", ""
+	}
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(out, "Usage: %s [flags] %s<day> <part> [input_file|-] \n", os.Args[0], year)
+	_, _ = fmt.Fprintf(out, "       %s list [-v]%s\n", os.Args[0], years)
+	_, _ = fmt.Fprintf(out, "       %s new %s<day>\n", os.Args[0], year)
+	_, _ = fmt.Fprintf(out, "       %s watch %s<day> [part]\n", os.Args[0], year)
+	flag.PrintDefaults()
+}
+
+func (c *command) usageErr(msg string) {
+	if len(msg) > 0 {
+		fmt.Fprintf(os.Stderr, "Invalid usage: %s\n", msg)
+	}
+	c.usage()
+	os.Exit(1)
+}
+
+// dir returns the directory of a year, relative to the working directory.
+func (c *command) dir(year int) string {
+	if c.year != 0 {
+		return "."
+	}
+	return strconv.Itoa(year)
+}
+
+// parseDay reads the year, unless it is fixed, and the day from the start of
+// the arguments, and returns the remaining arguments.
+func (c *command) parseDay(args []string) (year, day int, rest []string) {
+	year = c.year
+	if year == 0 {
+		if len(args) < 2 {
+			c.usageErr("<year> and <day> are required.")
+		}
+		var err error
+		year, err = strconv.Atoi(args[0])
+		if err != nil || registry.NDays(year) == 0 {
+			c.usageErr(fmt.Sprintf("<year> can be one of %v", registry.Years()))
+		}
+		args = args[1:]
+	}
+	if len(args) < 1 {
+		c.usageErr("<day> is required.")
+	}
+	nDays := registry.NDays(year)
+	day, err := strconv.Atoi(args[0])
+	if err != nil || day <= 0 || day > nDays {
+		if c.year != 0 {
+			c.usageErr(fmt.Sprintf("<day> can be 1~%d", nDays))
+		}
+		c.usageErr(fmt.Sprintf("<day> can be 1~%d for year %d", nDays, year))
+	}
+	return year, day, args[1:]
+}
+
+func (c *command) parsePart(arg string) int {
+	part, err := strconv.Atoi(arg)
+	if err != nil || part <= 0 || part > 2 {
+		c.usageErr("<part> can be 1 or 2")
+	}
+	return part
+}
+
+func (c *command) list(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	verbose := fs.Bool("v", false, "Show assumptions about the input")
+	_ = fs.Parse(args)
+
+	years := make([]int, 0, fs.NArg())
+	if c.year != 0 {
+		years = append(years, c.year)
+	} else {
+		for _, arg := range fs.Args() {
+			year, err := strconv.Atoi(arg)
+			if err != nil {
+				c.usageErr(fmt.Sprintf("invalid year %q", arg))
+			}
+			years = append(years, year)
+		}
+	}
+	if err := runner.List(os.Stdout, *verbose, years...); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (c *command) run(args []string) {
+	if c.year == 0 && len(args) < 3 {
+		c.usageErr("<year>, <day> and <part> are required.")
+	}
+	if c.year != 0 && len(args) < 2 {
+		c.usageErr("<day> and <part> are required.")
+	}
+	year, day, rest := c.parseDay(args)
+	part := c.parsePart(rest[0])
+
+	input := runner.DefaultInput(c.dir(year), day)
+	if len(rest) >= 2 {
+		input = rest[1]
+	}
+	result, err := runner.Run(year, day, part, input, &c.opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(result)
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"log"
+
+	"github.com/kanna5/advent_of_code/common/registry"
+	"github.com/kanna5/advent_of_code/common/scaffold"
+)
+
+// newDay scaffolds a day in its year's directory.
+func (c *command) newDay(args []string) {
+	year, day, _ := c.parseDay(args)
+	y, err := scaffold.Open(c.dir(year), year, registry.NDays(year))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := y.NewDay(day); err != nil {
+		log.Fatal(err)
+	}
+}
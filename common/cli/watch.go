@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/kanna5/advent_of_code/common/watch"
+)
+
+// watchDay re-runs a day whenever its sources or inputs change, until
+// interrupted.
+func (c *command) watchDay(args []string) {
+	year, day, rest := c.parseDay(args)
+	parts := []int{1, 2}
+	if len(rest) > 0 {
+		parts = []int{c.parsePart(rest[0])}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := watch.Run(ctx, watch.Config{Dir: c.dir(year), Day: day, Parts: parts}); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,134 @@
+// Package fetch downloads puzzle inputs and examples from adventofcode.com.
+package fetch
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Session returns the session cookie, taken from the COOKIE_SESSION
+// environment variable, or from the nearest .env file in dir or its parents.
+func Session(dir string) (string, error) {
+	if s := os.Getenv("COOKIE_SESSION"); s != "" {
+		return s, nil
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		s, err := readDotenv(filepath.Join(dir, ".env"))
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		if s != "" {
+			return s, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("COOKIE_SESSION is not set")
+		}
+		dir = parent
+	}
+}
+
+func readDotenv(pth string) (string, error) {
+	fd, err := os.Open(pth)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = fd.Close() }()
+
+	sc := bufio.NewScanner(fd)
+	for sc.Scan() {
+		key, val, ok := strings.Cut(strings.TrimSpace(sc.Text()), "=")
+		if ok && key == "COOKIE_SESSION" {
+			return strings.Trim(val, `"'`), nil
+		}
+	}
+	return "", sc.Err()
+}
+
+func get(url, session string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain")
+	req.Header.Set("User-Agent", "github.com/kanna5/advent_of_code")
+	if session != "" {
+		req.AddCookie(&http.Cookie{Name: "session", Value: session})
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Input downloads the puzzle input of a day.
+func Input(year, day int, session string) ([]byte, error) {
+	return get(fmt.Sprintf("https://adventofcode.com/%d/day/%d/input", year, day), session)
+}
+
+var (
+	reTitle     = regexp.MustCompile(`<h2>--- Day \d+: (.*?) ---</h2>`)
+	reCodeBlock = regexp.MustCompile(`(?s)<pre><code>(.*?)</code></pre>`)
+	reTag       = regexp.MustCompile(`<[^>]*>`)
+)
+
+type Puzzle struct {
+	Title string
+	// Example is the first code block in the description, which is the
+	// example input in most puzzles.
+	Example []byte
+}
+
+// Description downloads the puzzle description of a day and extracts its
+// title and example.
+func Description(year, day int, session string) (*Puzzle, error) {
+	page, err := get(fmt.Sprintf("https://adventofcode.com/%d/day/%d", year, day), session)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Puzzle{}
+	if m := reTitle.FindSubmatch(page); m != nil {
+		ret.Title = html.UnescapeString(string(m[1]))
+	}
+	m := reCodeBlock.FindSubmatch(page)
+	if m == nil {
+		return nil, fmt.Errorf("no example found in the puzzle description")
+	}
+	ret.Example = []byte(html.UnescapeString(reTag.ReplaceAllString(string(m[1]), "")))
+	return ret, nil
+}
+
+// ToFile saves the content downloaded by fn to pth, unless the file already
+// exists and is not empty. It returns whether the file was written.
+func ToFile(pth string, fn func() ([]byte, error)) (bool, error) {
+	if st, err := os.Stat(pth); err == nil && st.Size() > 0 {
+		return false, nil
+	}
+	content, err := fn()
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(pth), 0o755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(pth, content, 0o644)
+}
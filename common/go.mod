@@ -0,0 +1,3 @@
+module github.com/kanna5/advent_of_code/common
+
+go 1.25
@@ -0,0 +1,168 @@
+// Package registry links the solutions of every year together, so that they
+// can be looked up by (year, day) from any command.
+package registry
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+type Solver interface {
+	WithInput(i io.Reader)
+	SolvePart1() (string, error)
+	SolvePart2() (string, error)
+}
+
+// Options tune a solver, e.g. to play a variant of the puzzle. They are given
+// on the command line as key=value.
+type Options map[string]string
+
+// Check returns an error if an option is not one of the known keys.
+func (o Options) Check(known ...string) error {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if !slices.Contains(known, k) {
+			return fmt.Errorf("unknown option %q, can be one of %v", k, known)
+		}
+	}
+	return nil
+}
+
+// Configurable is implemented by solvers which take options.
+type Configurable interface {
+	// SetOptions replaces the options of the solver. It is called before
+	// solving, with no options to restore the defaults.
+	SetOptions(opts Options) error
+}
+
+type Tag string
+
+const (
+	Grid       Tag = "grid"
+	Graph      Tag = "graph"
+	Simulation Tag = "simulation"
+	Math       Tag = "math"
+	Geometry   Tag = "geometry"
+	DP         Tag = "dp"
+)
+
+// Status tells which parts of a puzzle are solved.
+type Status uint8
+
+const (
+	Solved      Status = iota // Both parts
+	Part1Solved               // Only part 1, part 2 is not done yet
+	Unsolved                  // Neither part, e.g. a freshly scaffolded day
+)
+
+// Meta describes a puzzle.
+type Meta struct {
+	Title string
+	URL   string // Filled in by Register if empty
+	Tags  []Tag
+
+	// NoPart2 is set for the last day of a year, where part 2 is a freebie
+	// and the solver only returns a congratulation message.
+	NoPart2 bool
+
+	// Status is set by the scaffold to Unsolved, and should be cleared when
+	// the solution is done.
+	Status Status
+
+	// Assumptions lists properties of the actual input that the solution
+	// relies on, but which are not stated in the puzzle.
+	Assumptions []string
+}
+
+func (m *Meta) HasTag(tag Tag) bool {
+	return slices.Contains(m.Tags, tag)
+}
+
+type Entry struct {
+	Year, Day int
+	Solver    Solver
+	Meta      Meta
+}
+
+type year struct {
+	nDays int
+	days  []*Entry // Use index 1 ~ nDays
+}
+
+var years = map[int]*year{}
+
+// RegisterYear declares a year with puzzles numbered 1 ~ nDays. It must be
+// called before registering any day of that year.
+func RegisterYear(y, nDays int) {
+	if _, ok := years[y]; ok {
+		panic(fmt.Sprintf("year %d is already registered", y))
+	}
+	years[y] = &year{nDays: nDays, days: make([]*Entry, nDays+1)}
+}
+
+// Register adds the solution for a day of a previously registered year.
+func Register(y, day int, s Solver, meta Meta) {
+	yr, ok := years[y]
+	if !ok {
+		panic(fmt.Sprintf("year %d is not registered", y))
+	}
+	if day <= 0 || day > yr.nDays {
+		panic(fmt.Sprintf("day %d is out of range for year %d", day, y))
+	}
+	if yr.days[day] != nil {
+		panic(fmt.Sprintf("day %d of year %d is already registered", day, y))
+	}
+	if meta.URL == "" {
+		meta.URL = fmt.Sprintf("https://adventofcode.com/%d/day/%d", y, day)
+	}
+	yr.days[day] = &Entry{Year: y, Day: day, Solver: s, Meta: meta}
+}
+
+// Years returns all registered years in ascending order.
+func Years() []int {
+	ret := make([]int, 0, len(years))
+	for y := range years {
+		ret = append(ret, y)
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+// NDays returns the number of puzzles in a year, or 0 if the year is unknown.
+func NDays(y int) int {
+	if yr, ok := years[y]; ok {
+		return yr.nDays
+	}
+	return 0
+}
+
+// Entries returns the entries of a year, indexed by day. Days which are not
+// implemented yet are nil.
+func Entries(y int) []*Entry {
+	yr, ok := years[y]
+	if !ok {
+		return nil
+	}
+	return slices.Clone(yr.days)
+}
+
+// Lookup finds the solution for a day. An error is returned if the day is out
+// of range or not implemented yet.
+func Lookup(y, day int) (*Entry, error) {
+	yr, ok := years[y]
+	if !ok {
+		return nil, fmt.Errorf("no solutions for year %d", y)
+	}
+	if day <= 0 || day > yr.nDays {
+		return nil, fmt.Errorf("<day> can be 1~%d for year %d", yr.nDays, y)
+	}
+	if yr.days[day] == nil {
+		return nil, fmt.Errorf("solution for day %d is not implemented yet", day)
+	}
+	return yr.days[day], nil
+}
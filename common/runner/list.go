@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kanna5/advent_of_code/common/registry"
+)
+
+// List prints the registered solutions of the given years, or of all years if
+// none are given. Assumptions about the input are included if verbose is set.
+func List(w io.Writer, verbose bool, years ...int) error {
+	if len(years) == 0 {
+		years = registry.Years()
+	}
+
+	var withAssumptions []*registry.Entry
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "YEAR\tDAY\tTITLE\tPART 1\tPART 2\tTAGS")
+	for _, y := range years {
+		entries := registry.Entries(y)
+		if entries == nil {
+			return fmt.Errorf("no solutions for year %d", y)
+		}
+		for day := 1; day < len(entries); day++ {
+			e := entries[day]
+			if e == nil {
+				_, _ = fmt.Fprintf(tw, "%d\t%d\t\t-\t-\t\n", y, day)
+				continue
+			}
+
+			part1, part2 := "done", "done"
+			switch e.Meta.Status {
+			case registry.Unsolved:
+				part1, part2 = "todo", "todo"
+			case registry.Part1Solved:
+				part2 = "todo"
+			}
+			if e.Meta.NoPart2 {
+				part2 = "n/a"
+			}
+			tags := make([]string, len(e.Meta.Tags))
+			for i, t := range e.Meta.Tags {
+				tags[i] = string(t)
+			}
+			_, _ = fmt.Fprintf(tw, "%d\t%d\t%s\t%s\t%s\t%s\n", y, day, e.Meta.Title, part1, part2, strings.Join(tags, ","))
+
+			if len(e.Meta.Assumptions) > 0 {
+				withAssumptions = append(withAssumptions, e)
+			}
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if verbose {
+		for _, e := range withAssumptions {
+			_, _ = fmt.Fprintf(w, "\n%d day %d (%s) assumes:\n", e.Year, e.Day, e.Meta.URL)
+			for _, a := range e.Meta.Assumptions {
+				_, _ = fmt.Fprintf(w, "  - %s\n", a)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// Package runner runs registered solutions against their puzzle input.
+package runner
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/kanna5/advent_of_code/common/registry"
+	"github.com/kanna5/advent_of_code/common/viz"
+)
+
+// DefaultInput returns where the input of a day is stored under dir.
+func DefaultInput(dir string, day int) string {
+	return path.Join(dir, "input", fmt.Sprintf("day-%02d.txt", day))
+}
+
+// ExampleInput returns where the example of a day is stored under dir. Some
+// puzzles give a different example for part 2, stored as the second example.
+func ExampleInput(dir string, day, n int) string {
+	name := fmt.Sprintf("day-%02d.example.txt", day)
+	if n > 1 {
+		name = fmt.Sprintf("day-%02d.example%d.txt", day, n)
+	}
+	return path.Join(dir, "input", name)
+}
+
+// ExampleFor returns the example to check a part with: the second example for
+// part 2 if there is one, or else the first.
+func ExampleFor(dir string, day, part int) string {
+	if part == 2 {
+		if pth := ExampleInput(dir, day, 2); exists(pth) {
+			return pth
+		}
+	}
+	return ExampleInput(dir, day, 1)
+}
+
+func exists(pth string) bool {
+	_, err := os.Stat(pth)
+	return err == nil
+}
+
+// OpenInput opens an input file. "-" stands for STDIN.
+func OpenInput(path_ string) (io.ReadCloser, error) {
+	if path_ == "-" {
+		log.Printf("Reading from STDIN")
+		return os.Stdin, nil
+	}
+	return os.Open(path_)
+}
+
+// Solve runs one part of a day's solution on the given input.
+func Solve(year, day, part int, input io.Reader) (string, error) {
+	entry, err := registry.Lookup(year, day)
+	if err != nil {
+		return "", err
+	}
+
+	solver := entry.Solver
+	solver.WithInput(input)
+	switch part {
+	case 1:
+		return solver.SolvePart1()
+	case 2:
+		return solver.SolvePart2()
+	}
+	return "", fmt.Errorf("<part> can be 1 or 2")
+}
+
+// Options for running a solution, set from command line flags.
+type Options struct {
+	Viz    string
+	VizOut string
+	Solver registry.Options
+}
+
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Viz, "viz", "", "Render visual output, if the solution has any. Can be png, svg, dot, gif, ansi or obj")
+	fs.StringVar(&o.VizOut, "viz-out", "", "Directory for visual output (default: current directory, or STDOUT for ansi)")
+	fs.Func("o", "Option of the solution, as `key=value`. Can be repeated", func(s string) error {
+		k, v, ok := strings.Cut(s, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("must be key=value")
+		}
+		if o.Solver == nil {
+			o.Solver = registry.Options{}
+		}
+		o.Solver[k] = v
+		return nil
+	})
+}
+
+// Configure sets the options of a day's solution. Solutions which take options
+// are reset to their defaults if opts is empty.
+func Configure(year, day int, opts registry.Options) error {
+	entry, err := registry.Lookup(year, day)
+	if err != nil {
+		return err
+	}
+	if c, ok := entry.Solver.(registry.Configurable); ok {
+		if err := c.SetOptions(opts); err != nil {
+			return fmt.Errorf("invalid options: %v", err)
+		}
+		return nil
+	}
+	if len(opts) > 0 {
+		return fmt.Errorf("day %d takes no options", day)
+	}
+	return nil
+}
+
+// Visualize renders the visual output of a day's solution.
+func Visualize(year, day, part int, input io.Reader, opts *Options) error {
+	f, err := viz.ParseFormat(opts.Viz)
+	if err != nil {
+		return err
+	}
+	entry, err := registry.Lookup(year, day)
+	if err != nil {
+		return err
+	}
+	v, ok := entry.Solver.(viz.Visualizer)
+	if !ok {
+		return fmt.Errorf("day %d has no visual output", day)
+	}
+
+	entry.Solver.WithInput(input)
+	figures, err := v.Visualize(part)
+	if err != nil {
+		return err
+	}
+	prefix := fmt.Sprintf("%d-day%02d-part%d", year, day, part)
+	return viz.Save(figures, f, opts.VizOut, prefix, os.Stdout)
+}
+
+// Run is like Solve, but reads the input from a file. Visual output is
+// rendered first if requested in opts, which may be nil.
+func Run(year, day, part int, inputPath string, opts *Options) (string, error) {
+	// Configure checks the solution exists before opening the input, so that
+	// a missing solution is not reported as a missing input file.
+	var solverOpts registry.Options
+	if opts != nil {
+		solverOpts = opts.Solver
+	}
+	if err := Configure(year, day, solverOpts); err != nil {
+		return "", err
+	}
+
+	input, err := OpenInput(inputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read input file: %v", err)
+	}
+	defer func() { _ = input.Close() }()
+
+	if opts == nil || opts.Viz == "" {
+		return Solve(year, day, part, input)
+	}
+	// The input is used twice.
+	content, err := io.ReadAll(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to read input file: %v", err)
+	}
+	if err := Visualize(year, day, part, bytes.NewReader(content), opts); err != nil {
+		return "", fmt.Errorf("failed to visualize: %v", err)
+	}
+	return Solve(year, day, part, bytes.NewReader(content))
+}
@@ -0,0 +1,200 @@
+// Package scaffold creates the files of a new day in a year's module.
+package scaffold
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/kanna5/advent_of_code/common/answers"
+	"github.com/kanna5/advent_of_code/common/fetch"
+	"github.com/kanna5/advent_of_code/common/runner"
+)
+
+//go:embed templates/*.tpl
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.tpl"))
+
+// Files created for each day, and the templates they are created from.
+var dayFiles = [][2]string{
+	{"solution.go", "solution.go.tpl"},
+	{"types.go", "types.go.tpl"},
+	{"solution_test.go", "solution_test.go.tpl"},
+}
+
+type templateArgs struct {
+	Year      int
+	Module    string
+	DayNumber int
+	Day       string
+	Title     string
+}
+
+// Year is the module of one year.
+type Year struct {
+	Dir    string
+	Module string
+	Year   int
+	NDays  int
+}
+
+// Open reads the module path of a year from the go.mod in dir.
+func Open(dir string, year, nDays int) (*Year, error) {
+	fd, err := os.Open(path.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = fd.Close() }()
+
+	sc := bufio.NewScanner(fd)
+	for sc.Scan() {
+		if mod, ok := strings.CutPrefix(sc.Text(), "module "); ok {
+			return &Year{Dir: dir, Module: strings.TrimSpace(mod), Year: year, NDays: nDays}, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("no module directive in %q", fd.Name())
+}
+
+func (y *Year) dayDir(day int) string {
+	return path.Join(y.Dir, "solutions", fmt.Sprintf("day%02d", day))
+}
+
+// NewDay creates the solution, types and test of a day, adds an empty entry
+// to answers.json, downloads the input and example, then updates the imports.
+// It refuses to overwrite any existing file.
+func (y *Year) NewDay(day int) error {
+	if day <= 0 || day > y.NDays {
+		return fmt.Errorf("<day> can be 1~%d for year %d", y.NDays, y.Year)
+	}
+	dayDir := y.dayDir(day)
+	for _, f := range dayFiles {
+		pth := path.Join(dayDir, f[0])
+		if _, err := os.Stat(pth); err == nil {
+			return fmt.Errorf("refusing to overwrite %q", pth)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	args := templateArgs{
+		Year:      y.Year,
+		Module:    y.Module,
+		DayNumber: day,
+		Day:       fmt.Sprintf("%02d", day),
+	}
+	if title, err := y.fetch(day); err != nil {
+		log.Printf("Failed to download puzzle: %v", err)
+	} else {
+		args.Title = title
+	}
+
+	if err := os.MkdirAll(dayDir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range dayFiles {
+		pth := path.Join(dayDir, f[0])
+		if err := writeTemplate(pth, f[1], args); err != nil {
+			return err
+		}
+		log.Printf("Created %s", pth)
+	}
+
+	ansPath := path.Join(y.Dir, "answers.json")
+	ans, err := answers.Load(ansPath)
+	if err != nil {
+		return err
+	}
+	if ans.Add(day) {
+		if err := ans.Save(ansPath); err != nil {
+			return err
+		}
+	}
+
+	return y.WriteImports()
+}
+
+func writeTemplate(pth, tpl string, args templateArgs) error {
+	// O_EXCL: never overwrite, even if the file appeared after the check.
+	fd, err := os.OpenFile(pth, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fd.Close() }()
+	return templates.ExecuteTemplate(fd, tpl, args)
+}
+
+// fetch downloads the input and example of a day if they are not there yet,
+// and returns the title of the puzzle.
+func (y *Year) fetch(day int) (string, error) {
+	session, err := fetch.Session(y.Dir)
+	if err != nil {
+		return "", err
+	}
+
+	inputPath := runner.DefaultInput(y.Dir, day)
+	written, err := fetch.ToFile(inputPath, func() ([]byte, error) {
+		return fetch.Input(y.Year, day, session)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to download input: %v", err)
+	}
+	if written {
+		log.Printf("Downloaded %s", inputPath)
+	}
+
+	puzzle, err := fetch.Description(y.Year, day, session)
+	if err != nil {
+		return "", err
+	}
+	examplePath := runner.ExampleInput(y.Dir, day, 1)
+	written, err = fetch.ToFile(examplePath, func() ([]byte, error) {
+		return puzzle.Example, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if written {
+		log.Printf("Saved example to %s", examplePath)
+	}
+	return puzzle.Title, nil
+}
+
+var reDayDir = regexp.MustCompile(`^day\d{2}$`)
+
+// WriteImports regenerates solutions/all/imports.go, which blank-imports
+// every day that has been created.
+func (y *Year) WriteImports() error {
+	entries, err := os.ReadDir(path.Join(y.Dir, "solutions"))
+	if err != nil {
+		return err
+	}
+
+	allDir := path.Join(y.Dir, "solutions", "all")
+	if err := os.MkdirAll(allDir, 0o755); err != nil {
+		return err
+	}
+	fd, err := os.OpenFile(path.Join(allDir, "imports.go"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fd.Close() }()
+
+	_, _ = fmt.Fprintf(fd, "package all\n\nimport (\n")
+	for _, e := range entries { // ReadDir returns entries sorted by name
+		if e.IsDir() && reDayDir.MatchString(e.Name()) {
+			_, _ = fmt.Fprintf(fd, "\t_ \"%s/solutions/%s\"\n", y.Module, e.Name())
+		}
+	}
+	_, err = fmt.Fprintf(fd, ")\n")
+	return err
+}
@@ -1,10 +1,11 @@
-// Solution for https://adventofcode.com/2025/day/{{.Day}}
+// Solution for https://adventofcode.com/{{.Year}}/day/{{.DayNumber}}
 package day{{.Day}}
 
 import (
 	"io"
 
-	"github.com/kanna5/advent_of_code/2025/solutions"
+	"{{.Module}}/solutions"
+	"github.com/kanna5/advent_of_code/common/registry"
 )
 
 type sol struct {
@@ -24,5 +25,8 @@ func (s *sol) WithInput(i io.Reader) {
 }
 
 func init() {
-	solutions.Days[{{.DayNumber}}] = &sol{}
+	registry.Register(solutions.Year, {{.DayNumber}}, &sol{}, registry.Meta{
+		Title:  {{printf "%q" .Title}},
+		Status: registry.Unsolved,
+	})
 }
@@ -0,0 +1,12 @@
+package day{{.Day}}
+
+import (
+	"testing"
+
+	"github.com/kanna5/advent_of_code/common/aoctest"
+	"github.com/kanna5/advent_of_code/common/registry"
+)
+
+func TestSolution(t *testing.T) {
+	aoctest.Run(t, {{.DayNumber}}, func() registry.Solver { return &sol{} })
+}
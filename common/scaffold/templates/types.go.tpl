@@ -0,0 +1 @@
+package day{{.Day}}
@@ -0,0 +1,87 @@
+package viz
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+)
+
+// maxGIFPixels caps the size of the frames of a GIF, which are all kept in
+// memory to be encoded. Longer animations only keep some of the frames, and
+// those with their own delay.
+var maxGIFPixels = 1 << 26
+
+// Animation is a sequence of grids.
+type Animation struct {
+	Frames []*Grid
+	Delay  int // Delay between frames in 100ths of a second, defaults to 10
+
+	// Delays optionally overrides Delay for each frame, e.g. to pause on
+	// important ones. Zero values fall back to Delay.
+	Delays []int
+}
+
+func (a *Animation) Formats() []Format {
+	return []Format{GIF, ANSI}
+}
+
+func (a *Animation) Render(w io.Writer, f Format) error {
+	switch f {
+	case GIF:
+		return a.renderGIF(w)
+	case ANSI:
+		for i, fr := range a.Frames {
+			if _, err := fmt.Fprintf(w, "Frame %d/%d\n", i+1, len(a.Frames)); err != nil {
+				return err
+			}
+			if err := fr.renderANSI(w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return unsupported(a, f)
+}
+
+func (a *Animation) renderGIF(w io.Writer) error {
+	if len(a.Frames) == 0 {
+		return fmt.Errorf("animation has no frames")
+	}
+	delay := a.Delay
+	if delay <= 0 {
+		delay = 10
+	}
+
+	// Frames may differ in size, e.g. with captions of different lengths.
+	// Draw them all on a canvas of the largest size.
+	var bounds image.Rectangle
+	for _, fr := range a.Frames {
+		size, _ := fr.layout()
+		bounds = bounds.Union(image.Rectangle{Max: size})
+	}
+	pal := paletteOf(a.Frames)
+
+	anim := &gif.GIF{}
+	step := (len(a.Frames)*bounds.Dx()*bounds.Dy()-1)/maxGIFPixels + 1
+	for i, fr := range a.Frames {
+		d := delay
+		own := i < len(a.Delays) && a.Delays[i] > 0
+		if own {
+			d = a.Delays[i]
+		}
+		if i%step != 0 && !own {
+			// Dropped frames keep the same duration.
+			anim.Delay[len(anim.Delay)-1] += d
+			continue
+		}
+
+		p := image.NewPaletted(bounds, pal)
+		fillRect(p, p.Rect, color.White)
+		fr.draw(p)
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, d)
+	}
+	return gif.EncodeAll(w, anim)
+}
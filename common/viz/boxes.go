@@ -0,0 +1,220 @@
+package viz
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"slices"
+)
+
+// Box is a solid made of unit cubes, from Min (inclusive) to Max (exclusive).
+type Box struct {
+	Min, Max [3]int
+	Color    color.Color
+	Name     string
+}
+
+// Boxes is a 3D scene of boxes, with the Z axis pointing up. It is exported as
+// a Wavefront OBJ model with vertex colors, or drawn in isometric projection.
+type Boxes struct {
+	Boxes   []Box
+	Scale   int // Size of a cube in pixels, defaults to 8
+	Caption string
+}
+
+func (b *Boxes) Formats() []Format {
+	return []Format{PNG, OBJ}
+}
+
+func (b *Boxes) Render(w io.Writer, f Format) error {
+	switch f {
+	case PNG:
+		return png.Encode(w, b.Image())
+	case OBJ:
+		return b.renderOBJ(w)
+	}
+	return unsupported(b, f)
+}
+
+func (b *Boxes) scale() int {
+	if b.Scale <= 0 {
+		return 8
+	}
+	return b.Scale
+}
+
+// Corners of a unit cube, and its faces as indices of corners in
+// counter-clockwise order seen from outside.
+var (
+	cubeCorners = [8][3]int{
+		{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0},
+		{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 1, 1},
+	}
+	cubeFaces = [6][4]int{
+		{0, 3, 2, 1}, {4, 5, 6, 7}, // bottom, top
+		{0, 1, 5, 4}, {2, 3, 7, 6}, // front, back
+		{1, 2, 6, 5}, {3, 0, 4, 7}, // right, left
+	}
+)
+
+func (b *Boxes) renderOBJ(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if b.Caption != "" {
+		fmt.Fprintf(bw, "# %s\n", b.Caption)
+	}
+	for i, box := range b.Boxes {
+		name := box.Name
+		if name == "" {
+			name = fmt.Sprintf("box%d", i)
+		}
+		fmt.Fprintf(bw, "o %s\n", name)
+		c := color.RGBAModel.Convert(White).(color.RGBA)
+		if box.Color != nil {
+			c = color.RGBAModel.Convert(box.Color).(color.RGBA)
+		}
+		for _, corner := range cubeCorners {
+			var v [3]int
+			for axis := range v {
+				v[axis] = box.Min[axis]
+				if corner[axis] == 1 {
+					v[axis] = box.Max[axis]
+				}
+			}
+			fmt.Fprintf(bw, "v %d %d %d %.3f %.3f %.3f\n", v[0], v[1], v[2],
+				float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+		}
+		for _, face := range cubeFaces {
+			base := i*len(cubeCorners) + 1
+			fmt.Fprintf(bw, "f %d %d %d %d\n", base+face[0], base+face[1], base+face[2], base+face[3])
+		}
+	}
+	return bw.Flush()
+}
+
+type voxel [3]int
+
+func (v voxel) add(o voxel) voxel {
+	return voxel{v[0] + o[0], v[1] + o[1], v[2] + o[2]}
+}
+
+// A face of a cube visible in the isometric projection: its normal, its
+// corners, and the neighbor sharing each edge (from corner i to i+1) in the
+// plane of the face.
+type isoFace struct {
+	normal    voxel
+	corners   [4]voxel
+	neighbors [4]voxel
+	shade     float64
+}
+
+var isoFaces = [...]isoFace{
+	{voxel{0, 0, 1}, [4]voxel{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 1, 1}}, [4]voxel{{0, -1, 0}, {1, 0, 0}, {0, 1, 0}, {-1, 0, 0}}, 0},
+	{voxel{1, 0, 0}, [4]voxel{{1, 0, 0}, {1, 1, 0}, {1, 1, 1}, {1, 0, 1}}, [4]voxel{{0, 0, -1}, {0, 1, 0}, {0, 0, 1}, {0, -1, 0}}, 0.2},
+	{voxel{0, 1, 0}, [4]voxel{{0, 1, 0}, {1, 1, 0}, {1, 1, 1}, {0, 1, 1}}, [4]voxel{{0, 0, -1}, {1, 0, 0}, {0, 0, 1}, {-1, 0, 0}}, 0.4},
+}
+
+// Image draws the boxes in isometric projection, seen from above the corner
+// with the greatest X and Y. Edges are drawn between different boxes only.
+func (b *Boxes) Image() *image.RGBA {
+	sc := b.scale()
+	project := func(v voxel) image.Point {
+		return image.Pt((v[0]-v[1])*sc, (v[0]+v[1])*sc/2-v[2]*sc)
+	}
+
+	owner := map[voxel]int{}
+	var bounds image.Rectangle
+	for i, box := range b.Boxes {
+		for x := box.Min[0]; x < box.Max[0]; x++ {
+			for y := box.Min[1]; y < box.Max[1]; y++ {
+				for z := box.Min[2]; z < box.Max[2]; z++ {
+					v := voxel{x, y, z}
+					owner[v] = i
+					for _, c := range cubeCorners {
+						p := project(v.add(c))
+						bounds = bounds.Union(image.Rectangle{p, p.Add(image.Pt(1, 1))})
+					}
+				}
+			}
+		}
+	}
+
+	var top, w int
+	if b.Caption != "" {
+		ts := textSize(b.Caption)
+		top = ts.Y + 2*captionPadding
+		w = ts.X + 2*captionPadding
+	}
+	img := image.NewRGBA(image.Rect(0, 0, max(w, bounds.Dx()), top+bounds.Dy()))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	if b.Caption != "" {
+		draw.Draw(img, image.Rect(0, 0, img.Rect.Dx(), top), image.NewUniform(captionBG), image.Point{}, draw.Src)
+		drawText(img, image.Pt(captionPadding, captionPadding), b.Caption, Black)
+	}
+	offset := image.Pt(0, top).Sub(bounds.Min)
+
+	// Painter's algorithm: cubes further from the viewer are drawn first.
+	voxels := make([]voxel, 0, len(owner))
+	for v := range owner {
+		voxels = append(voxels, v)
+	}
+	slices.SortFunc(voxels, func(a, b voxel) int {
+		return cmp.Or(cmp.Compare(a[0]+a[1]+a[2], b[0]+b[1]+b[2]), cmp.Compare(a[2], b[2]), cmp.Compare(a[0], b[0]))
+	})
+	for _, v := range voxels {
+		i := owner[v]
+		c := color.Color(White)
+		if b.Boxes[i].Color != nil {
+			c = b.Boxes[i].Color
+		}
+		for _, f := range isoFaces {
+			if _, hidden := owner[v.add(f.normal)]; hidden {
+				continue
+			}
+			var pts [4]image.Point
+			for j, corner := range f.corners {
+				pts[j] = project(v.add(corner)).Add(offset)
+			}
+			fillQuad(img, pts, Mix(c, Black, f.shade))
+			for j, n := range f.neighbors {
+				if o, ok := owner[v.add(n)]; !ok || o != i {
+					drawLine(img, pts[j], pts[(j+1)%4], Mix(c, Black, 0.6))
+				}
+			}
+		}
+	}
+	return img
+}
+
+// fillQuad fills a convex quadrilateral.
+func fillQuad(img draw.Image, pts [4]image.Point, c color.Color) {
+	r := image.Rectangle{pts[0], pts[0]}
+	for _, p := range pts[1:] {
+		r.Min = image.Pt(min(r.Min.X, p.X), min(r.Min.Y, p.Y))
+		r.Max = image.Pt(max(r.Max.X, p.X), max(r.Max.Y, p.Y))
+	}
+	cross := func(a, b, p image.Point) int {
+		return (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+	}
+	for y := r.Min.Y; y <= r.Max.Y; y++ {
+		for x := r.Min.X; x <= r.Max.X; x++ {
+			p := image.Pt(x, y)
+			pos, neg := false, false
+			for i := range pts {
+				switch s := cross(pts[i], pts[(i+1)%4], p); {
+				case s > 0:
+					pos = true
+				case s < 0:
+					neg = true
+				}
+			}
+			if !(pos && neg) {
+				img.Set(x, y, c)
+			}
+		}
+	}
+}
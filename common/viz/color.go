@@ -0,0 +1,122 @@
+package viz
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"slices"
+)
+
+// A small palette of distinguishable colors, for solutions that just need
+// "some" colors.
+var (
+	Black  = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	White  = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	Gray   = color.RGBA{0x9e, 0x9e, 0x9e, 0xff}
+	Red    = color.RGBA{0xe5, 0x39, 0x35, 0xff}
+	Orange = color.RGBA{0xfb, 0x8c, 0x00, 0xff}
+	Yellow = color.RGBA{0xfd, 0xd8, 0x35, 0xff}
+	Green  = color.RGBA{0x43, 0xa0, 0x47, 0xff}
+	Cyan   = color.RGBA{0x00, 0xac, 0xc1, 0xff}
+	Blue   = color.RGBA{0x1e, 0x88, 0xe5, 0xff}
+	Purple = color.RGBA{0x8e, 0x24, 0xaa, 0xff}
+)
+
+// Heat maps a value in [0, 1] to a color from blue (cold) to red (hot).
+func Heat(v float64) color.RGBA {
+	v = min(max(v, 0), 1)
+	stops := []color.RGBA{Blue, Cyan, Green, Yellow, Red}
+	pos := v * float64(len(stops)-1)
+	i := min(int(pos), len(stops)-2)
+	return Mix(stops[i], stops[i+1], pos-float64(i))
+}
+
+// Mix blends two colors, t = 0 being a and t = 1 being b.
+func Mix(a, b color.Color, t float64) color.RGBA {
+	ca, cb := color.RGBAModel.Convert(a).(color.RGBA), color.RGBAModel.Convert(b).(color.RGBA)
+	lerp := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return color.RGBA{lerp(ca.R, cb.R), lerp(ca.G, cb.G), lerp(ca.B, cb.B), lerp(ca.A, cb.A)}
+}
+
+func sameColor(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+func hexColor(c color.Color) string {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	return fmt.Sprintf("#%02x%02x%02x", rgba.R, rgba.G, rgba.B)
+}
+
+// paletteOf returns the colors of the frames if there are at most 256 of
+// them, or a generic palette otherwise.
+func paletteOf(frames []*Grid) color.Palette {
+	seen := map[color.RGBA]struct{}{}
+	for _, fr := range frames {
+		fr.colors(func(c color.Color) {
+			seen[color.RGBAModel.Convert(c).(color.RGBA)] = struct{}{}
+		})
+		if len(seen) > 256 {
+			return palette.Plan9
+		}
+	}
+	colors := make([]color.RGBA, 0, len(seen))
+	for c := range seen {
+		colors = append(colors, c)
+	}
+	// Map iteration order is random. Sort to keep the output deterministic.
+	slices.SortFunc(colors, func(a, b color.RGBA) int {
+		return int(uint32(a.R)<<24|uint32(a.G)<<16|uint32(a.B)<<8|uint32(a.A)) -
+			int(uint32(b.R)<<24|uint32(b.G)<<16|uint32(b.B)<<8|uint32(b.A))
+	})
+	ret := make(color.Palette, len(colors))
+	for i, c := range colors {
+		ret[i] = c
+	}
+	return ret
+}
+
+// fillRect fills a rectangle of img with a color. Paletted images are filled
+// directly, as looking up the color for each pixel is slow.
+func fillRect(img draw.Image, r image.Rectangle, c color.Color) {
+	p, ok := img.(*image.Paletted)
+	if !ok {
+		draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+		return
+	}
+	r = r.Intersect(p.Rect)
+	idx := uint8(p.Palette.Index(c))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := p.Pix[p.PixOffset(r.Min.X, y):p.PixOffset(r.Max.X, y)]
+		for i := range row {
+			row[i] = idx
+		}
+	}
+}
+
+const ansiReset = "\x1b[0m"
+
+func writeANSICell(w *bufio.Writer, c *Cell) {
+	if c.FG != nil {
+		rgba := color.RGBAModel.Convert(c.FG).(color.RGBA)
+		fmt.Fprintf(w, "\x1b[38;2;%d;%d;%dm", rgba.R, rgba.G, rgba.B)
+	}
+	if c.BG != nil {
+		rgba := color.RGBAModel.Convert(c.BG).(color.RGBA)
+		fmt.Fprintf(w, "\x1b[48;2;%d;%d;%dm", rgba.R, rgba.G, rgba.B)
+	}
+	ch := c.Ch
+	if ch == 0 {
+		ch = ' '
+	}
+	_, _ = w.WriteRune(ch)
+	if c.FG != nil || c.BG != nil {
+		_, _ = w.WriteString(ansiReset)
+	}
+}
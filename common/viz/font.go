@@ -0,0 +1,94 @@
+package viz
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// A 3x5 pixel font, enough for short captions. Lowercase letters are drawn as
+// uppercase, and unknown characters as blanks.
+var glyphs = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", ".##", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {".##", "#..", "#..", "#..", ".##"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {".##", "#..", "#.#", "#.#", ".##"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", ".#."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P': {"##.", "#.#", "##.", "#..", "#.."},
+	'Q': {".#.", "#.#", "#.#", "##.", ".##"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {".##", "#..", ".#.", "..#", "##."},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'=': {"...", "###", "...", "###", "..."},
+	'-': {"...", "...", "###", "...", "..."},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	'/': {"..#", "..#", ".#.", "#..", "#.."},
+	'.': {"...", "...", "...", "...", ".#."},
+	',': {"...", "...", "...", ".#.", "#.."},
+	'(': {".#.", "#..", "#..", "#..", ".#."},
+	')': {".#.", "..#", "..#", "..#", ".#."},
+	'#': {"#.#", "###", "#.#", "###", "#.#"},
+	'%': {"#.#", "..#", ".#.", "#..", "#.#"},
+}
+
+const (
+	glyphW, glyphH = 3, 5
+	textScale      = 2
+)
+
+// textSize returns the size in pixels of a line of text.
+func textSize(s string) image.Point {
+	n := len([]rune(s))
+	if n == 0 {
+		return image.Point{}
+	}
+	return image.Pt((n*(glyphW+1)-1)*textScale, glyphH*textScale)
+}
+
+// drawText draws a line of text with its top-left corner at pt.
+func drawText(img draw.Image, pt image.Point, s string, c color.Color) {
+	for i, ch := range []rune(strings.ToUpper(s)) {
+		g, ok := glyphs[ch]
+		if !ok {
+			continue
+		}
+		x0 := pt.X + i*(glyphW+1)*textScale
+		for y, row := range g {
+			for x := range glyphW {
+				if row[x] != '#' {
+					continue
+				}
+				r := image.Rect(x0+x*textScale, pt.Y+y*textScale, x0+(x+1)*textScale, pt.Y+(y+1)*textScale)
+				fillRect(img, r, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package viz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Attrs are GraphViz attributes, such as "shape" or "fillcolor".
+// ref: https://graphviz.org/doc/info/attrs.html
+type Attrs map[string]string
+
+type Node struct {
+	ID    string
+	Attrs Attrs
+}
+
+type Edge struct {
+	From, To string
+	Attrs    Attrs
+}
+
+// Cluster is a group of nodes drawn together in a box.
+type Cluster struct {
+	ID    string
+	Attrs Attrs // Cluster attributes, such as "label"
+	Nodes []string
+}
+
+// Graph is rendered to the DOT language of GraphViz, in the order nodes and
+// edges were added.
+type Graph struct {
+	Name     string
+	Attrs    Attrs // Graph attributes
+	Nodes    []Node
+	Edges    []Edge
+	Clusters []Cluster
+}
+
+func (g *Graph) AddNode(id string, attrs Attrs) {
+	g.Nodes = append(g.Nodes, Node{id, attrs})
+}
+
+func (g *Graph) AddEdge(from, to string, attrs Attrs) {
+	g.Edges = append(g.Edges, Edge{from, to, attrs})
+}
+
+func (g *Graph) AddCluster(id string, attrs Attrs, nodes ...string) {
+	g.Clusters = append(g.Clusters, Cluster{id, attrs, nodes})
+}
+
+func (g *Graph) Formats() []Format {
+	return []Format{DOT}
+}
+
+func (g *Graph) Render(w io.Writer, f Format) error {
+	if f != DOT {
+		return unsupported(g, f)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", strconv.Quote(g.Name))
+	for _, k := range slices.Sorted(maps.Keys(g.Attrs)) {
+		fmt.Fprintf(bw, "  %s=%s;\n", k, strconv.Quote(g.Attrs[k]))
+	}
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "  %s%s;\n", strconv.Quote(n.ID), formatAttrs(n.Attrs))
+	}
+	for _, c := range g.Clusters {
+		fmt.Fprintf(bw, "  subgraph %s {\n", strconv.Quote("cluster_"+c.ID))
+		for _, k := range slices.Sorted(maps.Keys(c.Attrs)) {
+			fmt.Fprintf(bw, "    %s=%s;\n", k, strconv.Quote(c.Attrs[k]))
+		}
+		for _, n := range c.Nodes {
+			fmt.Fprintf(bw, "    %s;\n", strconv.Quote(n))
+		}
+		fmt.Fprintln(bw, "  }")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "  %s -> %s%s;\n", strconv.Quote(e.From), strconv.Quote(e.To), formatAttrs(e.Attrs))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// formatAttrs formats attributes as " [k1=v1 k2=v2]", sorted by key.
+func formatAttrs(a Attrs) string {
+	if len(a) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(a))
+	for _, k := range slices.Sorted(maps.Keys(a)) {
+		parts = append(parts, k+"="+strconv.Quote(a[k]))
+	}
+	return " [" + strings.Join(parts, " ") + "]"
+}
+
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func escapeXML(s string) string {
+	return xmlEscaper.Replace(s)
+}
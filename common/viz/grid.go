@@ -0,0 +1,215 @@
+package viz
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// Cell of a grid. The background fills the cell in images. In terminal output
+// the character is drawn in the foreground color over the background. Nil
+// colors are left as default.
+type Cell struct {
+	Ch     rune
+	FG, BG color.Color
+}
+
+// Grid is a rectangle of cells, such as the map of a puzzle.
+type Grid struct {
+	W, H  int
+	Cells []Cell // Row-major
+	Scale int    // Size of a cell in pixels, defaults to 4
+
+	// Caption is shown above the grid, on a background of the Highlight
+	// color if set.
+	Caption   string
+	Highlight color.Color
+}
+
+func NewGrid(w, h int) *Grid {
+	return &Grid{W: w, H: h, Cells: make([]Cell, w*h)}
+}
+
+// GridFromLines creates a grid with the characters of the given lines.
+func GridFromLines(lines []string) *Grid {
+	w := 0
+	for _, l := range lines {
+		w = max(w, len([]rune(l)))
+	}
+	g := NewGrid(w, len(lines))
+	for y, l := range lines {
+		for x, ch := range []rune(l) {
+			g.At(x, y).Ch = ch
+		}
+	}
+	return g
+}
+
+func (g *Grid) Contains(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.W && y < g.H
+}
+
+// At returns the cell at (x, y) for modification. It panics if the position is
+// out of the grid.
+func (g *Grid) At(x, y int) *Cell {
+	if !g.Contains(x, y) {
+		panic(fmt.Sprintf("(%d, %d) is out of the %dx%d grid", x, y, g.W, g.H))
+	}
+	return &g.Cells[y*g.W+x]
+}
+
+func (g *Grid) Set(x, y int, c Cell) {
+	*g.At(x, y) = c
+}
+
+func (g *Grid) Clone() *Grid {
+	ret := *g
+	ret.Cells = append([]Cell(nil), g.Cells...)
+	return &ret
+}
+
+func (g *Grid) Formats() []Format {
+	return []Format{PNG, ANSI, SVG, GIF}
+}
+
+func (g *Grid) scale() int {
+	if g.Scale <= 0 {
+		return 4
+	}
+	return g.Scale
+}
+
+// cellColor is the color of a cell in images.
+func cellColor(c *Cell) color.Color {
+	switch {
+	case c.BG != nil:
+		return c.BG
+	case c.FG != nil:
+		return c.FG
+	}
+	return color.White
+}
+
+const captionPadding = 4
+
+var captionBG = color.RGBA{0xee, 0xee, 0xee, 0xff}
+
+// layout returns the size of the image of the grid, and the height of the
+// caption above the cells.
+func (g *Grid) layout() (size image.Point, top int) {
+	sc := g.scale()
+	var w int
+	if g.Caption != "" {
+		ts := textSize(g.Caption)
+		top = ts.Y + 2*captionPadding
+		w = ts.X + 2*captionPadding
+	}
+	return image.Pt(max(w, g.W*sc), top+g.H*sc), top
+}
+
+func (g *Grid) captionColor() color.Color {
+	if g.Highlight != nil {
+		return g.Highlight
+	}
+	return captionBG
+}
+
+// Image draws each cell as a square of its color, below the caption.
+func (g *Grid) Image() *image.RGBA {
+	size, _ := g.layout()
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	g.draw(img)
+	return img
+}
+
+// draw draws the grid at the top-left corner of img, which is large enough.
+func (g *Grid) draw(img draw.Image) {
+	sc := g.scale()
+	size, top := g.layout()
+	fillRect(img, image.Rectangle{Max: size}, color.White)
+
+	if g.Caption != "" {
+		fillRect(img, image.Rect(0, 0, size.X, top), g.captionColor())
+		drawText(img, image.Pt(captionPadding, captionPadding), g.Caption, Black)
+	}
+	for y := range g.H {
+		for x := range g.W {
+			r := image.Rect(x*sc, top+y*sc, (x+1)*sc, top+(y+1)*sc)
+			fillRect(img, r, cellColor(g.At(x, y)))
+		}
+	}
+}
+
+// colors calls add with each color of the image of the grid.
+func (g *Grid) colors(add func(color.Color)) {
+	add(color.White)
+	if g.Caption != "" {
+		add(g.captionColor())
+		add(Black)
+	}
+	for i := range g.Cells {
+		add(cellColor(&g.Cells[i]))
+	}
+}
+
+func (g *Grid) Render(w io.Writer, f Format) error {
+	switch f {
+	case PNG:
+		return png.Encode(w, g.Image())
+	case GIF:
+		return (&Animation{Frames: []*Grid{g}}).Render(w, GIF)
+	case ANSI:
+		return g.renderANSI(w)
+	case SVG:
+		return g.renderSVG(w)
+	}
+	return unsupported(g, f)
+}
+
+func (g *Grid) renderANSI(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if g.Caption != "" {
+		for _, ch := range g.Caption {
+			writeANSICell(bw, &Cell{Ch: ch, BG: g.Highlight})
+		}
+		_, _ = bw.WriteString("\n")
+	}
+	for y := range g.H {
+		for x := range g.W {
+			writeANSICell(bw, g.At(x, y))
+		}
+		_, _ = bw.WriteString(ansiReset + "\n")
+	}
+	return bw.Flush()
+}
+
+func (g *Grid) renderSVG(w io.Writer) error {
+	sc := g.scale()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		g.W*sc, g.H*sc, g.W, g.H)
+	if g.Caption != "" {
+		fmt.Fprintf(bw, "<title>%s</title>\n", escapeXML(g.Caption))
+	}
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", g.W, g.H)
+	for y := range g.H {
+		for x := 0; x < g.W; {
+			// Merge runs of the same color to keep the file small.
+			c := cellColor(g.At(x, y))
+			run := 1
+			for x+run < g.W && sameColor(cellColor(g.At(x+run, y)), c) {
+				run++
+			}
+			if !sameColor(c, color.White) {
+				fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="1" fill="%s"/>`+"\n", x, y, run, hexColor(c))
+			}
+			x += run
+		}
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
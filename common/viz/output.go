@@ -0,0 +1,101 @@
+package viz
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+)
+
+// Save renders the figures that support format f. Files are written to dir,
+// named "<prefix>-<figure name>.<ext>". Terminal output goes to stdout instead
+// if dir is empty, without colors if stdout is not a terminal.
+func Save(figures []Figure, f Format, dir, prefix string, stdout io.Writer) error {
+	if !IsTerminal(stdout) {
+		stdout = &plainWriter{w: stdout}
+	}
+	nSaved := 0
+	for _, fig := range figures {
+		if !Supports(fig.Scene, f) {
+			continue
+		}
+		nSaved++
+
+		if f == ANSI && dir == "" {
+			if _, err := fmt.Fprintf(stdout, "== %s ==\n", fig.Name); err != nil {
+				return err
+			}
+			if err := fig.Scene.Render(stdout, f); err != nil {
+				return fmt.Errorf("failed to render %q: %v", fig.Name, err)
+			}
+			continue
+		}
+
+		if dir == "" {
+			dir = "."
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		pth := path.Join(dir, fmt.Sprintf("%s-%s.%s", prefix, fig.Name, f.Ext()))
+		if err := saveFile(pth, fig.Scene, f); err != nil {
+			return fmt.Errorf("failed to render %q: %v", fig.Name, err)
+		}
+		log.Printf("Saved %s", pth)
+	}
+
+	if nSaved == 0 {
+		return fmt.Errorf("no visual output in format %q", f)
+	}
+	return nil
+}
+
+func saveFile(pth string, s Scene, f Format) error {
+	fd, err := os.OpenFile(pth, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := s.Render(fd, f); err != nil {
+		_ = fd.Close()
+		return err
+	}
+	return fd.Close()
+}
+
+// IsTerminal tells whether w is a terminal, or something else like a file or a
+// pipe.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	st, err := f.Stat()
+	return err == nil && st.Mode()&os.ModeCharDevice != 0
+}
+
+// plainWriter drops the escape sequences of terminal output, leaving plain
+// text.
+type plainWriter struct {
+	w        io.Writer
+	inEscape bool
+}
+
+func (p *plainWriter) Write(b []byte) (int, error) {
+	out := make([]byte, 0, len(b))
+	for _, c := range b {
+		switch {
+		case c == 0x1b:
+			p.inEscape = true
+		case !p.inEscape:
+			out = append(out, c)
+		case c >= 0x40 && c <= 0x7e && c != '[':
+			// Final byte of the sequence
+			p.inEscape = false
+		}
+	}
+	if _, err := p.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,186 @@
+package viz
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// Path is a sequence of connected points.
+type Path struct {
+	Points []image.Point
+	Stroke color.Color // Defaults to black
+	Fill   color.Color // Only for closed paths. Nil for no fill.
+	Closed bool
+	Width  float64 // Stroke width in pixels, defaults to 1
+	Title  string  // Shown as a tooltip in SVG
+}
+
+// Polylines is a drawing made of paths, in a coordinate system where y grows
+// downwards.
+type Polylines struct {
+	Paths []Path
+	// MaxSize is the larger side of the output in pixels. Coordinates are
+	// scaled to fit. Defaults to 1000.
+	MaxSize int
+}
+
+func (p *Polylines) Formats() []Format {
+	return []Format{SVG, PNG}
+}
+
+func (p *Polylines) Render(w io.Writer, f Format) error {
+	switch f {
+	case SVG:
+		return p.renderSVG(w)
+	case PNG:
+		return png.Encode(w, p.Image())
+	}
+	return unsupported(p, f)
+}
+
+// Bounds returns the smallest rectangle containing all points.
+func (p *Polylines) Bounds() image.Rectangle {
+	first := true
+	var r image.Rectangle
+	for _, path := range p.Paths {
+		for _, pt := range path.Points {
+			pr := image.Rectangle{pt, pt.Add(image.Pt(1, 1))}
+			if first {
+				r, first = pr, false
+			} else {
+				r = r.Union(pr)
+			}
+		}
+	}
+	return r
+}
+
+func (p *Polylines) scale() float64 {
+	maxSize := p.MaxSize
+	if maxSize <= 0 {
+		maxSize = 1000
+	}
+	b := p.Bounds()
+	side := max(b.Dx(), b.Dy(), 1)
+	return float64(maxSize) / float64(side)
+}
+
+func (p *Polylines) renderSVG(w io.Writer) error {
+	b := p.Bounds()
+	sc := p.scale()
+	margin := max(b.Dx(), b.Dy()) / 50
+	vb := b.Inset(-margin)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="%d %d %d %d">`+"\n",
+		float64(vb.Dx())*sc, float64(vb.Dy())*sc, vb.Min.X, vb.Min.Y, vb.Dx(), vb.Dy())
+	fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="#ffffff"/>`+"\n", vb.Min.X, vb.Min.Y, vb.Dx(), vb.Dy())
+	for _, path := range p.Paths {
+		tag := "polyline"
+		if path.Closed {
+			tag = "polygon"
+		}
+		fill := "none"
+		if path.Closed && path.Fill != nil {
+			fill = hexColor(path.Fill)
+		}
+		stroke := color.Color(Black)
+		if path.Stroke != nil {
+			stroke = path.Stroke
+		}
+		width := path.Width
+		if width <= 0 {
+			width = 1
+		}
+
+		fmt.Fprintf(bw, `<%s fill="%s" stroke="%s" stroke-width="%g" vector-effect="non-scaling-stroke" points="`, tag, fill, hexColor(stroke), width)
+		for i, pt := range path.Points {
+			if i > 0 {
+				_ = bw.WriteByte(' ')
+			}
+			fmt.Fprintf(bw, "%d,%d", pt.X, pt.Y)
+		}
+		if path.Title != "" {
+			fmt.Fprintf(bw, `"><title>%s</title></%s>`+"\n", escapeXML(path.Title), tag)
+		} else {
+			fmt.Fprint(bw, `"/>`+"\n")
+		}
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// Image rasterizes the paths. Fills are not drawn.
+func (p *Polylines) Image() *image.RGBA {
+	b := p.Bounds()
+	sc := p.scale()
+	img := image.NewRGBA(image.Rect(0, 0, int(float64(b.Dx())*sc)+1, int(float64(b.Dy())*sc)+1))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	toImg := func(pt image.Point) image.Point {
+		return image.Pt(int(float64(pt.X-b.Min.X)*sc), int(float64(pt.Y-b.Min.Y)*sc))
+	}
+	for _, path := range p.Paths {
+		stroke := color.Color(Black)
+		if path.Stroke != nil {
+			stroke = path.Stroke
+		}
+		n := len(path.Points)
+		if n == 1 {
+			pt := toImg(path.Points[0])
+			img.Set(pt.X, pt.Y, stroke)
+		}
+		for i := 1; i < n; i++ {
+			drawLine(img, toImg(path.Points[i-1]), toImg(path.Points[i]), stroke)
+		}
+		if path.Closed && n > 2 {
+			drawLine(img, toImg(path.Points[n-1]), toImg(path.Points[0]), stroke)
+		}
+	}
+	return img
+}
+
+// drawLine draws a line with Bresenham's algorithm.
+// ref: https://en.wikipedia.org/wiki/Bresenham%27s_line_algorithm
+func drawLine(img draw.Image, a, b image.Point, c color.Color) {
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	e := dx + dy
+	for {
+		img.Set(a.X, a.Y, c)
+		if a == b {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			a.X += sx
+		}
+		if e2 <= dx {
+			e += dx
+			a.Y += sy
+		}
+	}
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func sign(a int) int {
+	switch {
+	case a < 0:
+		return -1
+	case a > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+// Package viz renders the internal state of solutions as images, diagrams or
+// terminal output.
+//
+// A solver exposes visual output by implementing Visualizer. It describes what
+// to draw with the scenes in this package (Grid, Polylines, Graph, Animation
+// and Boxes), and the runner renders them in the format chosen by the user.
+// Rendering is deterministic: the same scene always produces the same bytes.
+package viz
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+type Format string
+
+const (
+	PNG  Format = "png"
+	SVG  Format = "svg"
+	DOT  Format = "dot"
+	GIF  Format = "gif"
+	ANSI Format = "ansi"
+	OBJ  Format = "obj"
+)
+
+var AllFormats = []Format{PNG, SVG, DOT, GIF, ANSI, OBJ}
+
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(s))
+	if !slices.Contains(AllFormats, f) {
+		return "", fmt.Errorf("unknown format %q, can be one of %v", s, AllFormats)
+	}
+	return f, nil
+}
+
+// Ext returns the file extension for a format.
+func (f Format) Ext() string {
+	if f == ANSI {
+		return "txt"
+	}
+	return string(f)
+}
+
+// Scene is something that can be rendered.
+type Scene interface {
+	// Formats lists the formats supported by the scene, preferred first.
+	Formats() []Format
+	Render(w io.Writer, f Format) error
+}
+
+// Figure is a named scene produced by a solver.
+type Figure struct {
+	Name  string
+	Scene Scene
+}
+
+// Visualizer is implemented by solvers that can show how they work. The input
+// is set with WithInput, as for solving.
+type Visualizer interface {
+	Visualize(part int) ([]Figure, error)
+}
+
+func unsupported(s Scene, f Format) error {
+	return fmt.Errorf("format %q is not supported, can be one of %v", f, s.Formats())
+}
+
+// Supports tells whether a scene can be rendered in a format.
+func Supports(s Scene, f Format) bool {
+	return slices.Contains(s.Formats(), f)
+}
@@ -0,0 +1,139 @@
+package viz
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/gif"
+	"os"
+	"path"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden renders a scene and compares the output with
+// testdata/<name>.<ext>.golden.
+func checkGolden(t *testing.T, name string, s Scene, f Format) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := s.Render(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	pth := path.Join("testdata", name+"."+f.Ext()+".golden")
+	if *update {
+		if err := os.WriteFile(pth, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("%s output differs from %s:\n%q", f, pth, buf.Bytes())
+	}
+}
+
+func TestGrid(t *testing.T) {
+	g := GridFromLines([]string{"#.S", ".#E"})
+	g.At(0, 0).BG = Gray
+	g.At(1, 1).BG = Gray
+	g.At(2, 0).FG = Green
+	g.At(2, 1).FG, g.At(2, 1).BG = White, Red
+	g.Caption = "loss 7"
+	g.Highlight = Yellow
+
+	for _, f := range []Format{ANSI, PNG, SVG} {
+		t.Run(string(f), func(t *testing.T) {
+			checkGolden(t, "grid", g, f)
+		})
+	}
+}
+
+func TestAnimation(t *testing.T) {
+	a := &Animation{Delay: 5, Delays: []int{0, 50}}
+	for i, caption := range []string{"#0", "#1 repeats"} {
+		g := GridFromLines([]string{"O.", ".#"})
+		g.At(i, 0).BG = Blue
+		g.At(1, 1).BG = Gray
+		g.Caption = caption
+		a.Frames = append(a.Frames, g)
+	}
+	a.Frames[1].Highlight = Yellow
+	checkGolden(t, "animation", a, GIF)
+}
+
+// Long animations drop frames, but not those with their own delay, and keep
+// the duration.
+func TestAnimationDecimated(t *testing.T) {
+	defer func(n int) { maxGIFPixels = n }(maxGIFPixels)
+	maxGIFPixels = 100 * 40 * 40
+
+	a := &Animation{Delay: 2}
+	for i := range 200 {
+		g := NewGrid(10, 10)
+		g.At(i%10, i/20).BG = Red
+		a.Frames = append(a.Frames, g)
+	}
+	a.Delays = make([]int, len(a.Frames))
+	a.Delays[101] = 100
+
+	var buf bytes.Buffer
+	if err := a.Render(&buf, GIF); err != nil {
+		t.Fatal(err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) > 101 {
+		t.Errorf("%d frames, expected at most 101", len(g.Image))
+	}
+	total, long := 0, 0
+	for _, d := range g.Delay {
+		total += d
+		if d >= 100 {
+			long++
+		}
+	}
+	if want := 199*2 + 100; total != want {
+		t.Errorf("total delay %d, expected %d", total, want)
+	}
+	if long != 1 {
+		t.Errorf("%d frames with the long delay, expected 1", long)
+	}
+}
+
+func TestPolylines(t *testing.T) {
+	p := &Polylines{
+		Paths: []Path{
+			{Points: []image.Point{{7, 1}, {11, 1}, {11, 7}, {9, 7}, {9, 5}, {2, 5}, {2, 3}, {7, 3}}, Closed: true, Fill: Green},
+			{Points: []image.Point{{9, 5}, {2, 3}}, Stroke: Red, Width: 2, Title: "area <24>"},
+		},
+		MaxSize: 100,
+	}
+	checkGolden(t, "polylines", p, SVG)
+}
+
+func TestGraph(t *testing.T) {
+	g := &Graph{Name: "workflows", Attrs: Attrs{"rankdir": "LR"}}
+	g.AddNode("in", Attrs{"shape": "box", "label": "in"})
+	g.AddNode("A", nil)
+	g.AddNode("R", Attrs{"color": "red"})
+	g.AddCluster("px", Attrs{"label": `"px" rules`}, "in")
+	g.AddEdge("in", "A", Attrs{"label": "s<1351"})
+	g.AddEdge("in", "R", nil)
+	checkGolden(t, "graph", g, DOT)
+}
+
+func TestBoxes(t *testing.T) {
+	b := &Boxes{
+		Boxes: []Box{
+			{Min: [3]int{1, 0, 1}, Max: [3]int{2, 3, 2}, Color: Blue, Name: "A"},
+			{Min: [3]int{0, 0, 2}, Max: [3]int{3, 1, 3}},
+		},
+		Caption: "bricks",
+	}
+	checkGolden(t, "boxes", b, OBJ)
+}
@@ -0,0 +1,233 @@
+// Package watch re-runs a day's solution whenever its source or input changes.
+//
+// Changes are detected by polling modification times, which is good enough
+// for a handful of files and needs no platform-specific notification API.
+package watch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kanna5/advent_of_code/common/answers"
+	"github.com/kanna5/advent_of_code/common/runner"
+)
+
+type Config struct {
+	Dir      string // Module directory of the year
+	Day      int
+	Parts    []int
+	Interval time.Duration
+	Out      io.Writer
+}
+
+func (c *Config) dayPkg() string {
+	return "./" + path.Join("solutions", fmt.Sprintf("day%02d", c.Day))
+}
+
+// paths returns the files and directories to watch.
+func (c *Config) paths() []string {
+	return []string{
+		path.Join(c.Dir, c.dayPkg()),
+		path.Join(c.Dir, "lib"),
+		runner.DefaultInput(c.Dir, c.Day),
+		runner.ExampleInput(c.Dir, c.Day, 1),
+		runner.ExampleInput(c.Dir, c.Day, 2),
+		path.Join(c.Dir, "answers.json"),
+	}
+}
+
+// snapshot summarizes the state of the watched files. Any change, addition or
+// removal of a file results in a different snapshot.
+func snapshot(paths []string) string {
+	var b strings.Builder
+	for _, p := range paths {
+		_ = filepath.WalkDir(p, func(pth string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil // Missing files are fine, they may be created later.
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			fmt.Fprintf(&b, "%s:%d:%d\n", pth, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+	}
+	return b.String()
+}
+
+type result struct {
+	answer  string
+	elapsed time.Duration
+	err     error
+}
+
+type watcher struct {
+	Config
+	bin      string
+	previous map[int]result
+}
+
+func (w *watcher) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(w.Out, format, a...)
+}
+
+func (w *watcher) command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = w.Dir
+	return cmd
+}
+
+// testExamples solves the examples with the built solution and checks the
+// answers recorded in answers.json, so that regressions are reported before
+// running the actual input. Examples which can't be checked are reported as
+// skipped.
+func (w *watcher) testExamples(ctx context.Context) {
+	ans, err := answers.Load(path.Join(w.Dir, "answers.json"))
+	if err != nil {
+		w.printf("Examples skipped: %v\n", err)
+		return
+	}
+	a := ans.Get(w.Day)
+	if a == nil {
+		a = &answers.Answers{}
+	}
+	for _, part := range w.Parts {
+		expected := []string{1: a.Example1, 2: a.Example2}[part]
+		input := runner.ExampleFor(w.Dir, w.Day, part)
+		if expected == "" {
+			w.printf("Example %d: skipped, answer is not recorded in answers.json\n", part)
+			continue
+		}
+		if _, err := os.Stat(input); err != nil {
+			w.printf("Example %d: skipped, %s is missing\n", part, input)
+			continue
+		}
+		rel, err := filepath.Rel(w.Dir, input)
+		if err != nil {
+			rel = input
+		}
+		r := w.solve(ctx, part, rel)
+		switch {
+		case r.err != nil:
+			w.printf("Example %d: FAILED with error: %v\n", part, r.err)
+		case r.answer != expected:
+			w.printf("Example %d: FAILED, got %s, expected %s\n", part, r.answer, expected)
+		default:
+			w.printf("Example %d: passed\n", part)
+		}
+	}
+}
+
+func (w *watcher) build(ctx context.Context) bool {
+	out, err := w.command(ctx, "go", "build", "-o", w.bin, ".").CombinedOutput()
+	if err != nil {
+		w.printf("Build FAILED:\n%s\n", bytes.TrimSpace(out))
+		return false
+	}
+	return true
+}
+
+// solve runs the built solution with an input relative to the module
+// directory.
+func (w *watcher) solve(ctx context.Context, part int, input string) result {
+	var stdout, stderr bytes.Buffer
+	cmd := w.command(ctx, w.bin, strconv.Itoa(w.Day), strconv.Itoa(part), input)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	ret := result{elapsed: time.Since(start)}
+	if err != nil {
+		ret.err = fmt.Errorf("%v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	} else {
+		ret.answer = strings.TrimSpace(stdout.String())
+	}
+	return ret
+}
+
+func (w *watcher) run(ctx context.Context) {
+	w.printf("\n=== %s day %d ===\n", time.Now().Format(time.TimeOnly), w.Day)
+	if !w.build(ctx) {
+		return
+	}
+	w.testExamples(ctx)
+
+	for _, part := range w.Parts {
+		r := w.solve(ctx, part, runner.DefaultInput(".", w.Day))
+		prev, hasPrev := w.previous[part]
+		w.previous[part] = r
+
+		if r.err != nil {
+			w.printf("Part %d: error after %v: %v\n", part, r.elapsed, r.err)
+			continue
+		}
+		change := ""
+		switch {
+		case !hasPrev || prev.err != nil:
+		case prev.answer == r.answer:
+			change = " (unchanged)"
+		default:
+			change = fmt.Sprintf(" (was %s)", prev.answer)
+			a, errA := strconv.ParseInt(r.answer, 10, 64)
+			b, errB := strconv.ParseInt(prev.answer, 10, 64)
+			if errA == nil && errB == nil {
+				change = fmt.Sprintf(" (was %s, %+d)", prev.answer, a-b)
+			}
+		}
+		w.printf("Part %d: %s%s in %v\n", part, r.answer, change, r.elapsed.Round(time.Microsecond))
+	}
+}
+
+// Run builds and runs the solution, then does it again on every change, until
+// ctx is done.
+func Run(ctx context.Context, cfg Config) error {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 500 * time.Millisecond
+	}
+	if cfg.Out == nil {
+		cfg.Out = os.Stdout
+	}
+	if len(cfg.Parts) == 0 {
+		cfg.Parts = []int{1, 2}
+	}
+
+	tmp, err := os.MkdirTemp("", "aoc-watch-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	w := &watcher{
+		Config:   cfg,
+		bin:      filepath.Join(tmp, "solution"),
+		previous: map[int]result{},
+	}
+	paths := cfg.paths()
+	w.printf("Watching %s\n", strings.Join(paths, ", "))
+
+	last := ""
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if s := snapshot(paths); s != last {
+			last = s
+			w.run(ctx)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
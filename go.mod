@@ -0,0 +1,24 @@
+module github.com/kanna5/advent_of_code
+
+go 1.25
+
+require (
+	github.com/kanna5/advent_of_code/2023 v0.0.0
+	github.com/kanna5/advent_of_code/2025 v0.0.0
+	github.com/kanna5/advent_of_code/common v0.0.0
+)
+
+require (
+	github.com/k0kubun/pp/v3 v3.5.0 // indirect
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	golang.org/x/exp v0.0.0-20251125195548-87e1e737ad39 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+)
+
+replace (
+	github.com/kanna5/advent_of_code/2023 => ./2023
+	github.com/kanna5/advent_of_code/2025 => ./2025
+	github.com/kanna5/advent_of_code/common => ./common
+)